@@ -0,0 +1,209 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// What an Argument does with its destination field when it is seen.
+// Several Arguments can share one destination field, by giving the
+// same Dest; for example, --debug and --quiet can both use ActionStoreConst
+// to set a LogLevel field.
+type Action int
+
+const (
+	// Parse the value and store it in the destination field. For a slice
+	// field, each value is appended. This is the default.
+	ActionStore Action = iota
+
+	// Store the Const value in the destination field. No value is given
+	// on the command-line.
+	ActionStoreConst
+
+	// Append the Const value to the destination slice field. No value is
+	// given on the command-line.
+	ActionAppendConst
+
+	// Add 1 to the destination integer field each time the switch is
+	// seen, so -vvv gives 3. No value is given on the command-line.
+	ActionCount
+
+	// For a slice field, append each value to the slice. This is the
+	// same as ActionStore, but checks that the field is a slice.
+	ActionAppend
+
+	// For a slice field, each time the switch is seen, its values replace
+	// the current contents of the slice (including the default contents).
+	ActionReplace
+
+	// Parse and store the value as ActionStore does, then pass the value
+	// (or the item appended to a slice) to the Callback function.
+	ActionCallback
+)
+
+// Check that the Action can be used with this Argument. This is called
+// after the value type is known.
+func (self *Argument) sanityCheckAction() error {
+	switch self.Action {
+	case ActionStore:
+		return nil
+	case ActionStoreConst, ActionAppendConst, ActionCount:
+		if !self.isSwitch() {
+			return fmt.Errorf("Argument %s: only switch arguments can use this Action",
+				self.PrettyName())
+		}
+		if self.NumArgs != 0 {
+			return fmt.Errorf("Argument %s: NumArgs cannot be used with this Action",
+				self.PrettyName())
+		}
+		return nil
+	case ActionAppend, ActionReplace:
+		if self.value.storageType() != Slice {
+			return fmt.Errorf("Argument %s: the destination must be a slice for this Action",
+				self.PrettyName())
+		}
+		return nil
+	case ActionCallback:
+		if self.Callback == nil {
+			return fmt.Errorf("Argument %s: ActionCallback needs a Callback",
+				self.PrettyName())
+		}
+		return nil
+	default:
+		return fmt.Errorf("Argument %s: unknown Action %d", self.PrettyName(), self.Action)
+	}
+}
+
+// The value that was most recently stored for this argument: the
+// last item of a slice, or the whole value otherwise.
+func (self *Argument) lastValue() interface{} {
+	return lastValueOf(self.value)
+}
+
+func lastValueOf(valueType valueType) interface{} {
+	value := valueType.getValue()
+	if valueType.storageType() == Slice && value.Kind() == reflect.Slice {
+		if value.Len() == 0 {
+			return nil
+		}
+		return value.Index(value.Len() - 1).Interface()
+	}
+	return value.Interface()
+}
+
+// =========================================================== const
+
+type constValueT struct {
+	valueT
+	constant reflect.Value
+	append   bool
+	pointer  bool
+}
+
+func newConstValueT(valueP reflect.Value, constant interface{}, append bool) (*constValueT, error) {
+	if constant == nil {
+		return nil, errors.New("Const must be set for this Action")
+	}
+	targetType := valueP.Type()
+	if append {
+		if targetType.Kind() != reflect.Slice {
+			return nil, errors.New("the destination must be a slice for ActionAppendConst")
+		}
+		targetType = targetType.Elem()
+	}
+	constValue := reflect.ValueOf(constant)
+
+	// A pointer destination gets a new copy of the Const each time
+	pointer := false
+	if !append && targetType.Kind() == reflect.Ptr && constValue.Type().Kind() != reflect.Ptr {
+		targetType = targetType.Elem()
+		pointer = true
+	}
+	if !constValue.Type().ConvertibleTo(targetType) {
+		return nil, fmt.Errorf("Const of type %s cannot be stored in %s",
+			constValue.Type().String(), targetType.String())
+	}
+	return &constValueT{
+		valueT:   valueT{valueP},
+		constant: constValue.Convert(targetType),
+		append:   append,
+		pointer:  pointer,
+	}, nil
+}
+
+func (self *constValueT) defaultSwitchNumArgs() int {
+	return 0
+}
+
+func (self *constValueT) seenWithoutValue(m *Messages) error {
+	if self.append {
+		self.value.Set(reflect.Append(self.value, self.constant))
+	} else if self.pointer {
+		item := reflect.New(self.constant.Type())
+		item.Elem().Set(self.constant)
+		self.value.Set(item)
+	} else {
+		self.value.Set(self.constant)
+	}
+	return nil
+}
+
+func (self *constValueT) parse(m *Messages, text string) error {
+	return errors.New("Does not take a value")
+}
+
+func (self *constValueT) setChoices(m *Messages, choicesIntf interface{}) error {
+	return errors.New("Choices cannot be used with a Const")
+}
+
+func (self *constValueT) storageType() valueStorageType {
+	if self.append {
+		return Slice
+	}
+	return Scalar
+}
+
+// =========================================================== count
+
+type countValueT struct {
+	valueT
+}
+
+func newCountValueT(valueP reflect.Value) (*countValueT, error) {
+	switch valueP.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &countValueT{valueT: valueT{valueP}}, nil
+	default:
+		return nil, errors.New("the destination must be an integer for ActionCount")
+	}
+}
+
+func (self *countValueT) defaultSwitchNumArgs() int {
+	return 0
+}
+
+func (self *countValueT) seenWithoutValue(m *Messages) error {
+	switch self.value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		self.value.SetUint(self.value.Uint() + 1)
+	default:
+		self.value.SetInt(self.value.Int() + 1)
+	}
+	return nil
+}
+
+func (self *countValueT) parse(m *Messages, text string) error {
+	return errors.New("Does not take a value")
+}
+
+func (self *countValueT) setChoices(m *Messages, choicesIntf interface{}) error {
+	return errors.New("Choices cannot be used with ActionCount")
+}
+
+func (self *countValueT) storageType() valueStorageType {
+	return Scalar
+}
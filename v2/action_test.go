@@ -0,0 +1,227 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+)
+
+type LogLevel int
+
+const (
+	LogQuiet LogLevel = iota
+	LogNormal
+	LogDebug
+)
+
+type ActTestOptions struct {
+	LogLevel  LogLevel
+	Verbosity int
+	Features  []string
+	Includes  []string
+	Names     []string
+	Flag      bool
+}
+
+func createActTestParser() (*ActTestOptions, *ArgumentParser) {
+	opts := &ActTestOptions{
+		LogLevel: LogNormal,
+		Includes: []string{"/usr/include"},
+	}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--debug"},
+		Dest:     "LogLevel",
+		Action:   ActionStoreConst,
+		Const:    LogDebug,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--quiet"},
+		Dest:     "LogLevel",
+		Action:   ActionStoreConst,
+		Const:    LogQuiet,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v"},
+		Dest:     "Verbosity",
+		Action:   ActionCount,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--fast"},
+		Dest:     "Features",
+		Action:   ActionAppendConst,
+		Const:    "fast",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--safe"},
+		Dest:     "Features",
+		Action:   ActionAppendConst,
+		Const:    "safe",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-I"},
+		Dest:     "Includes",
+		Action:   ActionAppend,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--names"},
+		Action:   ActionReplace,
+		NumArgs:  2,
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestActionStoreConst(c *C) {
+	opts, ap := createActTestParser()
+
+	argv := []string{"--debug"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, LogDebug)
+	c.Check(ap.Root.Seen["LogLevel"], Equals, true)
+}
+
+func (s *MySuite) TestActionStoreConstLastWins(c *C) {
+	opts, ap := createActTestParser()
+
+	argv := []string{"--debug", "--quiet"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, LogQuiet)
+}
+
+func (s *MySuite) TestActionCount(c *C) {
+	opts, ap := createActTestParser()
+
+	argv := []string{"-vvv", "-v"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbosity, Equals, 4)
+}
+
+func (s *MySuite) TestActionAppendConst(c *C) {
+	opts, ap := createActTestParser()
+
+	argv := []string{"--safe", "--fast", "--safe"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Features, DeepEquals, []string{"safe", "fast", "safe"})
+}
+
+func (s *MySuite) TestActionAppend(c *C) {
+	opts, ap := createActTestParser()
+
+	argv := []string{"-I", "/opt/include", "-I/tmp"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Includes, DeepEquals, []string{"/usr/include", "/opt/include", "/tmp"})
+}
+
+func (s *MySuite) TestActionReplace(c *C) {
+	opts, ap := createActTestParser()
+	opts.Names = []string{"default"}
+
+	argv := []string{"--names", "a", "b", "--names", "c", "d"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Names, DeepEquals, []string{"c", "d"})
+}
+
+func (s *MySuite) TestActionCallback(c *C) {
+	opts, ap := createActTestParser()
+	var got []interface{}
+	ap.Add(&Argument{
+		Switches: []string{"--name"},
+		Dest:     "Names",
+		Action:   ActionCallback,
+		Callback: func(value interface{}) error {
+			got = append(got, value)
+			return nil
+		},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--flag"},
+		Action:   ActionCallback,
+		Callback: func(value interface{}) error {
+			got = append(got, value)
+			return nil
+		},
+	})
+
+	argv := []string{"--name", "x", "--flag", "--name", "y"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Names, DeepEquals, []string{"x", "y"})
+	c.Check(got, DeepEquals, []interface{}{"x", true, "y"})
+}
+
+func (s *MySuite) TestActionCallbackError(c *C) {
+	_, ap := createActTestParser()
+	ap.Add(&Argument{
+		Switches: []string{"--flag"},
+		Action:   ActionCallback,
+		Callback: func(value interface{}) error {
+			return errors.New("not today")
+		},
+	})
+
+	results := ap.parseArgv([]string{"--flag"})
+	c.Check(results.parseError, ErrorMatches, "--flag: not today")
+}
+
+func (s *MySuite) TestActionBadDefinitions(c *C) {
+	_, ap := createActTestParser()
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--trace"},
+			Dest:     "LogLevel",
+			Action:   ActionStoreConst,
+		})
+	}, PanicMatches, "Argument --trace: Const must be set for this Action")
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--trace"},
+			Dest:     "LogLevel",
+			Action:   ActionStoreConst,
+			Const:    "trace",
+		})
+	}, PanicMatches, "Argument --trace: Const of type string cannot be stored in argparse.LogLevel")
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--count"},
+			Dest:     "Names",
+			Action:   ActionCount,
+		})
+	}, PanicMatches, "Argument --count: the destination must be an integer for ActionCount")
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--one"},
+			Dest:     "Verbosity",
+			Action:   ActionReplace,
+		})
+	}, PanicMatches, "Argument --one: the destination must be a slice for this Action")
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--cb"},
+			Dest:     "Verbosity",
+			Action:   ActionCallback,
+		})
+	}, PanicMatches, "Argument --cb: ActionCallback needs a Callback")
+}
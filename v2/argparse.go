@@ -43,6 +43,40 @@ type ArgumentParser struct {
 	// The switch strings that can invoke help
 	HelpSwitches []string
 
+	// Allow long switches (those starting with "--") to be abbreviated
+	// by the user, as long as the abbreviation is a prefix of only one
+	// Argument's switches. Each Command can override this.
+	AllowAbbreviations bool
+
+	// Allow the long HelpSwitches to be abbreviated too.
+	AbbreviateHelpSwitches bool
+
+	// Allow sub-command names to be abbreviated, as long as the
+	// abbreviation is a prefix of only one sub-command name.
+	AbbreviateSubCommands bool
+
+	// Look up the value of an environment variable, for the Env of an
+	// Argument, or the EnvPrefix of a Command. The default is os.LookupEnv.
+	LookupEnv func(name string) (string, bool)
+
+	// Replace each argument like @args.txt with the arguments in the
+	// file, which are split like a shell would split them. An argument
+	// starting with @@ is kept, without the first @.
+	AllowResponseFiles bool
+
+	// A switch, Name, or Dest of a string Argument of the root Command
+	// that gives the path of a configuration file. If it is inherited,
+	// it can be given to a sub-command too.
+	ConfigArgument string
+
+	// The configuration files to look for, in order, if ConfigArgument
+	// is not given. The first one that exists is read.
+	ConfigFiles []string
+
+	// The format of the configuration file. The default is to choose
+	// it by the extension of the file.
+	ConfigFormat ConfigFormat
+
 	// The root Command object.
 	Root *Command
 
@@ -82,57 +116,136 @@ func (self *ArgumentParser) New(c *Command) *Command {
 // On a request for help (-h), print the help and exit with os.Exit(0).
 // On a user input error, print the error message and exit with os.Exit(1).
 func (self *ArgumentParser) Parse() {
-	self.parseRunFunction(true)
+	exitCode, err := self.run(os.Args[1:], false)
+	if err != nil {
+		os.Exit(exitCode)
+	}
 }
 
-// Parse and run the function.
-func (self *ArgumentParser) parseRunFunction(shouldReturn bool) {
-	results := self.parseArgv(os.Args[1:])
+// Parse the os.Argv arguments, call the Function for the triggered
+// Command, and then exit. An error returned from the Function causes us
+// to exit with 1, otherwise, exit with 0.
+// On a request for help (-h), print the help and exit with os.Exit(0).
+// On a user input error, print the error message and exit with os.Exit(1).
+func (self *ArgumentParser) ParseAndExit() {
+	exitCode, _ := self.run(os.Args[1:], true)
+	os.Exit(exitCode)
+}
 
-	cmd := results.triggeredCommand
+// Parse the given arguments (which should not include the program name),
+// filling out the Values of the triggered Command and its ancestors.
+// Nothing is printed and the Function of the triggered Command is not
+// called. The ParseResult is always returned, even with an error.
+// The error, if any, is a *HelpRequestedError or a *ParseError.
+func (self *ArgumentParser) ParseArgs(argv []string) (*ParseResult, error) {
+	results := self.parseArgv(argv)
+
+	result := &ParseResult{
+		Command:   results.triggeredCommand,
+		Ancestors: results.ancestorCommands,
+	}
 
 	if results.helpRequested {
-		helpString := self.helpString(cmd, results.ancestorCommands)
-		fmt.Fprintln(self.Stdout, helpString)
-		os.Exit(0)
+		return result, &HelpRequestedError{
+			Command: result.Command,
+			Help:    self.helpString(result.Command, result.Ancestors),
+		}
 	} else if results.parseError != nil {
-		fmt.Fprintln(self.Stderr, results.parseError.Error())
-		os.Exit(1)
+		return result, &ParseError{
+			Command: result.Command,
+			Err:     results.parseError,
+		}
 	}
+	return result, nil
+}
 
+// Split the command-line into arguments the way a POSIX shell would, with
+// single and double quotes and backslash escapes, but without expanding
+// variables or wildcards, and then parse them like ParseArgs does. The
+// command-line should not include the program name. A quote that is not
+// closed is a *ParseError that gives the column of the quote.
+func (self *ArgumentParser) ParseString(cmdline string) (*ParseResult, error) {
+	self.Messages.fillDefaults()
+	words, err := splitShellWords(&self.Messages, cmdline)
+	if err != nil {
+		splitErr := err.(*shellSplitError)
+		if splitErr.line == 1 {
+			err = fmt.Errorf(self.Messages.AtColumnFmt, splitErr.column, splitErr.err)
+		} else {
+			err = fmt.Errorf(self.Messages.AtLineColumnFmt, splitErr.line,
+				splitErr.column, splitErr.err)
+		}
+		return &ParseResult{Command: self.Root}, &ParseError{
+			Command: self.Root,
+			Err:     err,
+		}
+	}
+	argv := make([]string, len(words))
+	for i, word := range words {
+		argv[i] = word.text
+	}
+	return self.ParseArgs(argv)
+}
+
+// Parse the given arguments (which should not include the program name),
+// and call the Function for the triggered Command. Help and error messages
+// are printed to Stdout and Stderr, but os.Exit is never called; instead,
+// the exit code that the program should use is returned, along with
+// the error, if any: a *HelpRequestedError, *ParseError, *CallbackError,
+// or *NoFunctionError.
+func (self *ArgumentParser) Run(argv []string) (int, error) {
+	return self.run(argv, true)
+}
+
+// Parse and run the function. If requireFunction is true, it is an error
+// for the triggered Command not to have a Function.
+func (self *ArgumentParser) run(argv []string, requireFunction bool) (int, error) {
+	result, err := self.ParseArgs(argv)
+	if err != nil {
+		switch e := err.(type) {
+		case *HelpRequestedError:
+			fmt.Fprintln(self.Stdout, e.Help)
+			return 0, err
+		default:
+			fmt.Fprintln(self.Stderr, err.Error())
+			return 1, err
+		}
+	}
+
+	cmd := result.Command
 	if cmd.Function != nil {
-		err := cmd.Function(cmd, cmd.Values)
+		err = cmd.Function(cmd, cmd.Values)
 		if err != nil {
 			fmt.Fprintln(self.Stderr, err.Error())
-			os.Exit(1)
+			return 1, &CallbackError{Command: cmd, Err: err}
 		}
 		// Success
-		if shouldReturn {
-			return
-		} else {
-			os.Exit(0)
-		}
+		return 0, nil
 	}
+
 	// The chosen command had no function to run!
-	if !shouldReturn {
+	if requireFunction {
 		// Print the usage to stderr, and exit with non-0.
-		helpString := self.helpString(cmd, results.ancestorCommands)
+		helpString := self.helpString(cmd, result.Ancestors)
 		fmt.Fprintln(self.Stderr, helpString)
-		os.Exit(1)
+		return 1, &NoFunctionError{Command: cmd}
 	}
-}
-
-// Parse the os.Argv arguments, call the Function for the triggered
-// Command, and then exit. An error returned from the Function causes us
-// to exit with 1, otherwise, exit with 0.
-// On a request for help (-h), print the help and exit with os.Exit(0).
-// On a user input error, print the error message and exit with os.Exit(1).
-func (self *ArgumentParser) ParseAndExit() {
-	self.parseRunFunction(false)
+	return 0, nil
 }
 
 func (self *ArgumentParser) parseArgv(argv []string) *parseResults {
+	self.Messages.fillDefaults()
 	parser := parserState{}
+	if self.AllowResponseFiles {
+		var err error
+		argv, parser.origins, err = self.expandResponseFiles(argv)
+		if err != nil {
+			return &parseResults{
+				parseError:       err,
+				triggeredCommand: self.Root,
+			}
+		}
+	}
 	results := parser.runParser(self, argv)
 	return results
 }
@@ -3,6 +3,9 @@ package argparse
 // Copyright (c) 2017 by Gilbert Ramirez <gram@alumni.rice.edu>
 
 import (
+	"bytes"
+	"errors"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -59,3 +62,129 @@ func (s *MySuite) TestChooseHelpCustomAtRoot(c *C) {
 	c.Check(results.helpRequested, Equals, true)
 	c.Check(results.triggeredCommand, Equals, ap.Root)
 }
+
+func createAPRunTestParser(function ParserCallback) (*APTestOptions, *ArgumentParser, *bytes.Buffer, *bytes.Buffer) {
+	opts := &APTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+		Function:    function,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--bool1"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--string1"},
+	})
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	ap.Stdout = stdout
+	ap.Stderr = stderr
+	return opts, ap, stdout, stderr
+}
+
+func (s *MySuite) TestParseArgsSuccess(c *C) {
+	opts, ap, stdout, stderr := createAPRunTestParser(nil)
+
+	result, err := ap.ParseArgs([]string{"--bool1", "--string1", "abc"})
+	c.Assert(err, IsNil)
+	c.Check(result.Command, Equals, ap.Root)
+	c.Check(len(result.Ancestors), Equals, 0)
+	c.Check(opts.Bool1, Equals, true)
+	c.Check(opts.String1, Equals, "abc")
+	c.Check(stdout.Len(), Equals, 0)
+	c.Check(stderr.Len(), Equals, 0)
+}
+
+func (s *MySuite) TestParseArgsHelp(c *C) {
+	_, ap, stdout, _ := createAPRunTestParser(nil)
+
+	result, err := ap.ParseArgs([]string{"-h"})
+	c.Assert(err, NotNil)
+	c.Check(result.Command, Equals, ap.Root)
+
+	var helpErr *HelpRequestedError
+	c.Assert(errors.As(err, &helpErr), Equals, true)
+	c.Check(helpErr.Command, Equals, ap.Root)
+	c.Check(helpErr.Help, Matches, "(?s).*This is a test program.*")
+	c.Check(stdout.Len(), Equals, 0)
+}
+
+func (s *MySuite) TestParseArgsError(c *C) {
+	_, ap, _, stderr := createAPRunTestParser(nil)
+
+	_, err := ap.ParseArgs([]string{"--no-such-switch"})
+	c.Assert(err, NotNil)
+
+	var parseErr *ParseError
+	c.Assert(errors.As(err, &parseErr), Equals, true)
+	c.Check(parseErr.Error(), Equals, "No such switch: --no-such-switch")
+	c.Check(stderr.Len(), Equals, 0)
+}
+
+func (s *MySuite) TestRunCallsFunction(c *C) {
+	called := false
+	opts, ap, _, _ := createAPRunTestParser(func(cmd *Command, values Values) error {
+		called = true
+		c.Check(values.(*APTestOptions).String1, Equals, "xyz")
+		return nil
+	})
+
+	exitCode, err := ap.Run([]string{"--string1=xyz"})
+	c.Assert(err, IsNil)
+	c.Check(exitCode, Equals, 0)
+	c.Check(called, Equals, true)
+	c.Check(opts.String1, Equals, "xyz")
+}
+
+func (s *MySuite) TestRunCallbackError(c *C) {
+	failure := errors.New("callback failed")
+	_, ap, _, stderr := createAPRunTestParser(func(cmd *Command, values Values) error {
+		return failure
+	})
+
+	exitCode, err := ap.Run([]string{})
+	c.Check(exitCode, Equals, 1)
+
+	var cbErr *CallbackError
+	c.Assert(errors.As(err, &cbErr), Equals, true)
+	c.Check(cbErr.Command, Equals, ap.Root)
+	c.Check(errors.Is(err, failure), Equals, true)
+	c.Check(stderr.String(), Equals, "callback failed\n")
+}
+
+func (s *MySuite) TestRunHelp(c *C) {
+	_, ap, stdout, stderr := createAPRunTestParser(nil)
+
+	exitCode, err := ap.Run([]string{"--help"})
+	c.Check(exitCode, Equals, 0)
+
+	var helpErr *HelpRequestedError
+	c.Assert(errors.As(err, &helpErr), Equals, true)
+	c.Check(stdout.String(), Equals, helpErr.Help+"\n")
+	c.Check(stderr.Len(), Equals, 0)
+}
+
+func (s *MySuite) TestRunParseError(c *C) {
+	_, ap, stdout, stderr := createAPRunTestParser(nil)
+
+	exitCode, err := ap.Run([]string{"--string1"})
+	c.Check(exitCode, Equals, 1)
+
+	var parseErr *ParseError
+	c.Assert(errors.As(err, &parseErr), Equals, true)
+	c.Check(stderr.String(), Equals, "Expected a value after --string1\n")
+	c.Check(stdout.Len(), Equals, 0)
+}
+
+func (s *MySuite) TestRunNoFunction(c *C) {
+	_, ap, _, stderr := createAPRunTestParser(nil)
+
+	exitCode, err := ap.Run([]string{"--bool1"})
+	c.Check(exitCode, Equals, 1)
+
+	var noFuncErr *NoFunctionError
+	c.Assert(errors.As(err, &noFuncErr), Equals, true)
+	c.Check(noFuncErr.Command, Equals, ap.Root)
+	c.Check(stderr.Len() > 0, Equals, true)
+}
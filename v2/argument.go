@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"regexp"
 
 	//	"strconv"
 	"strings"
@@ -43,6 +44,25 @@ type Argument struct {
 	NumArgs     int
 	NumArgsGlob string
 
+	// For switch arguments, must the user give this switch? If a
+	// required switch is not seen, the parse fails.
+	Required bool
+
+	// Other arguments, given by a switch, Name, or Dest, that must also
+	// be given if this argument is given.
+	Requires []string
+
+	// Other arguments, given by a switch, Name, or Dest, that cannot be
+	// given if this argument is given.
+	ConflictsWith []string
+
+	// If any of these conditions is met, this argument must be given.
+	RequiredIf []Condition
+
+	// For bool (or *bool) switch arguments, also accept "--no-" in front of each
+	// long switch, to set the value to false.
+	Negatable bool
+
 	// Will a sub-command inherit this argument definition if one is not
 	// defined for that sub-command, *and* if the Value struct for that
 	// Command has a suitable field?
@@ -51,25 +71,143 @@ type Argument struct {
 	// For non-boolean options, the valid values that the user can provide.
 	// If Choices is given, and the user provides a value not in this list,
 	// the user will be presented with an error.
+	// Choices can also be a []Choice, or a map[string]string from the
+	// values to their descriptions, in which case the help shows each
+	// value with its description.
 	Choices interface{}
 
+	// A function that returns the Choices, in any of the forms that
+	// Choices can take. It is called when the argument is parsed, so the
+	// choices can come from the environment or a config file.
+	ChoicesFunc func() (interface{}, error)
+
+	// For numeric destinations (including time.Duration and ByteSize),
+	// the smallest and largest values that the user can give. These
+	// can be any integer or float type.
+	Min interface{}
+	Max interface{}
+
+	// For string destinations, a regular expression that the whole
+	// value must match.
+	Pattern string
+
+	// Called after each value is parsed and stored, with the value (or
+	// the item appended to a slice). An error returned from it is
+	// reported to the user as a bad value.
+	Validate func(value interface{}) error
+
+	// What to do with the destination field when the argument is seen.
+	// The default is ActionStore.
+	Action Action
+
+	// The value for ActionStoreConst and ActionAppendConst. It must be
+	// convertible to the type of the destination field (or slice item).
+	Const interface{}
+
+	// The function called by ActionCallback, after the value is parsed
+	// and stored. The value (or the item appended to a slice) is passed
+	// to it. An error returned from it is reported to the user.
+	Callback func(value interface{}) error
+
+	// For destinations of a named integer type, a map[string]T from the
+	// names the user can give to the values. This overrides the names
+	// given to RegisterEnum for the type.
+	EnumValues interface{}
+
+	// For enum destinations, accept the names in any case
+	EnumIgnoreCase bool
+
+	// For switch arguments, the environment variable to take the value
+	// from if the switch is not given on the command-line.
+	Env string
+
+	// For time.Time destinations, the layouts (as for time.Parse) that
+	// are tried in turn. The default is time.RFC3339.
+	TimeLayouts []string
+
+	// For ByteSize destinations, accept only the exact spellings of
+	// the units, like "kB" and "MiB", instead of any case, and "k" or "Mi".
+	StrictByteSize bool
+
+	// For map destinations, the text between the key and the value of
+	// each item. The default is "=".
+	KeyValueSeparator string
+
+	// For map destinations, what to do when a key is given more than
+	// once. The default is DuplicateKeyLastWins.
+	DuplicateKeys DuplicateKeyPolicy
+
 	// The methods for the specific storage type of this value of the Argument
 	// (bool, int, string, float64, etc.)
 	value valueType
+
+	// The choices (and their descriptions) for the help
+	choiceHelp []Choice
+
+	// The choices, as they were given to the value type
+	choiceValues interface{}
+
+	// Has the ChoicesFunc been called?
+	choicesLoaded bool
+
+	// The environment variable for this argument, from Env or the
+	// EnvPrefix of the Command
+	envName string
+
+	// The compiled Pattern
+	patternRegexp *regexp.Regexp
+
+	// The arguments named in Requires, ConflictsWith, and RequiredIf,
+	// found in the Command that this Argument was added to.
+	requiresArgs      []*Argument
+	conflictsWithArgs []*Argument
+	requiredIfArgs    []*Argument
+}
+
+// A condition on another argument, for Argument.RequiredIf
+type Condition struct {
+	// The other argument, given by a switch, Name, or Dest
+	Argument string
+
+	// If nil, the condition is met if the other argument is given.
+	// Otherwise, the other argument must be given and its value must
+	// be equal to this, or print the same as this, so that
+	// "file" can match a value of a string type.
+	Value interface{}
 }
 
 func (self *Argument) deepCopy() *Argument {
 	arg := &Argument{
-		Switches:    make([]string, len(self.Switches)),
-		Name:        self.Name,
-		Help:        self.Help,
-		MetaVar:     self.MetaVar,
-		Dest:        self.Dest,
-		NumArgs:     self.NumArgs,
-		NumArgsGlob: self.NumArgsGlob,
-		//		Required: self.Required,
-		Inherit: self.Inherit,
-		Choices: self.Choices,
+		Switches:      make([]string, len(self.Switches)),
+		Name:          self.Name,
+		Help:          self.Help,
+		MetaVar:       self.MetaVar,
+		Dest:          self.Dest,
+		NumArgs:       self.NumArgs,
+		NumArgsGlob:   self.NumArgsGlob,
+		Required:      self.Required,
+		Requires:      self.Requires,
+		ConflictsWith: self.ConflictsWith,
+		RequiredIf:    self.RequiredIf,
+		Negatable:     self.Negatable,
+		Action:        self.Action,
+		Const:         self.Const,
+		Callback:      self.Callback,
+		Inherit:       self.Inherit,
+		Choices:       self.Choices,
+		ChoicesFunc:   self.ChoicesFunc,
+		Min:           self.Min,
+		Max:           self.Max,
+		Pattern:       self.Pattern,
+		Validate:      self.Validate,
+
+		Env:               self.Env,
+		EnumValues:        self.EnumValues,
+		EnumIgnoreCase:    self.EnumIgnoreCase,
+		TimeLayouts:       self.TimeLayouts,
+		StrictByteSize:    self.StrictByteSize,
+		KeyValueSeparator: self.KeyValueSeparator,
+		DuplicateKeys:     self.DuplicateKeys,
 	}
 	copy(arg.Switches, self.Switches)
 	return arg
@@ -89,9 +227,49 @@ func (self *Argument) init(dest Values, messages *Messages) {
 		panic(err.Error())
 	}
 
+	if self.Env != "" && !self.isSwitch() {
+		panic(fmt.Sprintf("Argument %s cannot have an Env because it is not a switch",
+			self.PrettyName()))
+	}
+
+	if self.Negatable {
+		if !isBoolValueType(self.value) {
+			panic(fmt.Sprintf("Argument %s is Negatable but its destination is not a bool",
+				self.PrettyName()))
+		}
+		if len(self.negatedSwitches()) == 0 {
+			panic(fmt.Sprintf("Argument %s is Negatable but has no long switch",
+				self.PrettyName()))
+		}
+	}
+
+	err = self.sanityCheckAction()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = self.sanityCheckMap()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = self.sanityCheckValidators()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = self.sanityCheckEnum()
+	if err != nil {
+		panic(err.Error())
+	}
+
 	// Any Choices?
+	if self.Choices != nil && self.ChoicesFunc != nil {
+		panic(fmt.Sprintf("Argument %s cannot have both Choices and ChoicesFunc",
+			self.PrettyName()))
+	}
 	if self.Choices != nil {
-		err = self.value.setChoices(messages, self.Choices)
+		err = self.setChoices(messages, self.Choices)
 		if err != nil {
 			panic(fmt.Sprintf("Argument %s: %s", self.PrettyName(),
 				err.Error()))
@@ -119,6 +297,10 @@ func (self *Argument) sanityCheckNameAndSwitches() error {
 	if len(self.Switches) > 0 && self.Name != "" {
 		return errors.New("Name cannot be given if Switches is given")
 	}
+	if self.Required && self.Name != "" {
+		return fmt.Errorf("Positional argument %s cannot be Required; use NumArgs or NumArgsGlob",
+			self.Name)
+	}
 	return nil
 }
 
@@ -203,12 +385,12 @@ func (self *Argument) sanityCheckValueType(dest Values) error {
 	userStructValue := reflect.Indirect(userStructPtrValue)
 	userStructType := userStructValue.Type()
 
-	var field reflect.StructField
+	var fieldValue reflect.Value
 	var found bool
 	var needles []string
 
 	if self.Dest != "" {
-		field, found = userStructType.FieldByName(self.Dest)
+		fieldValue, _, found = findDestField(userStructValue, self.Dest, false)
 		if !found {
 			return errors.New(fmt.Sprintf("Could not find destination field for argument %s, given as %s",
 				self.PrettyName(), self.Dest))
@@ -217,17 +399,31 @@ func (self *Argument) sanityCheckValueType(dest Values) error {
 		for _, switchName := range self.Switches {
 			structName := argumentVariableName(switchName[1:])
 			needles = append(needles, structName)
-			field, found = userStructType.FieldByName(structName)
-			if found {
+			field, ok := userStructType.FieldByName(structName)
+			if ok {
+				fieldValue, found = userStructValue.FieldByIndex(field.Index), true
 				self.Dest = field.Name
 				break
 			}
+			// A dotted switch, like --db.host, can be for a field
+			// of a nested struct
+			if strings.Contains(switchName, ".") {
+				path := strings.TrimLeft(switchName, "-")
+				needles = append(needles, path)
+				var dest string
+				fieldValue, dest, found = findDestField(userStructValue, path, true)
+				if found {
+					self.Dest = dest
+					break
+				}
+			}
 		}
 		if !found && self.Name != "" {
 			structName := argumentVariableName(self.Name)
 			needles = append(needles, structName)
-			field, found = userStructType.FieldByName(structName)
-			if found {
+			field, ok := userStructType.FieldByName(structName)
+			if ok {
+				fieldValue, found = userStructValue.FieldByIndex(field.Index), true
 				self.Dest = field.Name
 			}
 		}
@@ -237,71 +433,68 @@ func (self *Argument) sanityCheckValueType(dest Values) error {
 		}
 	}
 
-	// By using the index of the field within the struct type,
-	// we can get the corresponding struct value
-	fieldValue := userStructValue.FieldByIndex(field.Index)
-	fieldType := fieldValue.Type()
-	fieldTypeKind := fieldType.Kind()
+	var err error
+	self.value, err = self.newValue(fieldValue)
+	return err
+}
 
-	switch fieldType.String() {
-	case "time.Duration":
-		self.value = newDurationValueT(fieldValue)
-		return nil
+// Create the value type for the field, for the Action
+func (self *Argument) newValue(fieldValue reflect.Value) (valueType, error) {
+	// Some actions don't parse a value, and don't care about the
+	// specific type of the field
+	switch self.Action {
+	case ActionStoreConst, ActionAppendConst:
+		value, err := newConstValueT(fieldValue, self.Const,
+			self.Action == ActionAppendConst)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %s: %w", self.PrettyName(), err)
+		}
+		return value, nil
+	case ActionCount:
+		value, err := newCountValueT(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %s: %w", self.PrettyName(), err)
+		}
+		return value, nil
 	}
 
-	// We may want to look at fieldType.String() for all types here,
-	// since we really do want the dynamic type not the concrete type
-	switch fieldTypeKind {
-	case reflect.Bool:
-		self.value = newBoolValueT(fieldValue)
-		return nil
-	case reflect.String:
-		self.value = newStringValueT(fieldValue)
-		return nil
-	case reflect.Int64:
-		self.value = newInt64ValueT(fieldValue)
-		return nil
-	case reflect.Int:
-		self.value = newIntValueT(fieldValue)
-		return nil
-	case reflect.Float64:
-		self.value = newFloatValueT(fieldValue)
+	value, err := newValueType(fieldValue, self)
+	if err != nil {
+		return nil, fmt.Errorf("Argument %s %s", self.PrettyName(), err)
+	}
+	return value, nil
+}
+
+// Forget what was learned during the last parse: the choices from the
+// ChoicesFunc, and the keys given for a map
+func (self *Argument) resetParse() {
+	self.choicesLoaded = false
+	if mapValue, ok := self.value.(*mapValueT); ok {
+		mapValue.given = nil
+	}
+}
+
+// The "--no-" versions of the long switches, if this argument is Negatable
+func (self *Argument) negatedSwitches() []string {
+	if !self.Negatable {
 		return nil
-	case reflect.Slice:
-		sliceType := fieldValue.Type().Elem()
-		switch sliceType.String() {
-		case "time.Duration":
-			self.value = newDurationSliceValueT(fieldValue)
-			return nil
+	}
+	var negations []string
+	for _, switchName := range self.Switches {
+		if strings.HasPrefix(switchName, "--") {
+			negations = append(negations, "--no-"+switchName[2:])
 		}
+	}
+	return negations
+}
 
-		sliceKind := fieldValue.Type().Elem().Kind()
-		switch sliceKind {
-		case reflect.Bool:
-			self.value = newBoolSliceValueT(fieldValue)
-			return nil
-		case reflect.Int64:
-			self.value = newInt64SliceValueT(fieldValue)
-			return nil
-		case reflect.Int:
-			self.value = newIntSliceValueT(fieldValue)
-			return nil
-		case reflect.String:
-			self.value = newStringSliceValueT(fieldValue)
-			return nil
-		case reflect.Float64:
-			self.value = newFloatSliceValueT(fieldValue)
-			return nil
-		default:
-			return fmt.Errorf("Argument %s cannot be of type []%s",
-				self.PrettyName(), sliceKind.String())
+func (self *Argument) isNegatedSwitch(text string) bool {
+	for _, negation := range self.negatedSwitches() {
+		if text == negation {
+			return true
 		}
-	default:
-		return errors.New(fmt.Sprintf("Argument %s cannot be of type %s",
-			self.PrettyName(), fieldType.String()))
 	}
-	panic("Should not reach here.")
-	return nil
+	return false
 }
 
 func (self *Argument) PrettyName() string {
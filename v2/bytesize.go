@@ -0,0 +1,134 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements ByteSize, a destination type for sizes given
+// with units, like "512k", "1.5G", or "10MiB".
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// A number of bytes. As a destination field, it accepts a number
+// followed by an optional SI unit (kB, MB, GB, TB, PB, EB; powers of
+// 1000) or IEC unit (KiB, MiB, GiB, TiB, PiB, EiB; powers of 1024).
+type ByteSize uint64
+
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000
+	MB          = KB * 1000
+	GB          = MB * 1000
+	TB          = GB * 1000
+	PB          = TB * 1000
+	EB          = PB * 1000
+
+	KiB ByteSize = 1024
+	MiB          = KiB * 1024
+	GiB          = MiB * 1024
+	TiB          = GiB * 1024
+	PiB          = TiB * 1024
+	EiB          = PiB * 1024
+)
+
+// The exact spellings of the units, which is all that ParseByteSize
+// accepts when it is strict
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"B":   Byte,
+	"kB":  KB,
+	"KB":  KB,
+	"MB":  MB,
+	"GB":  GB,
+	"TB":  TB,
+	"PB":  PB,
+	"EB":  EB,
+	"KiB": KiB,
+	"MiB": MiB,
+	"GiB": GiB,
+	"TiB": TiB,
+	"PiB": PiB,
+	"EiB": EiB,
+}
+
+// When not strict, the units can be in any case, and the "B" can be
+// left off, so "512k" and "2gi" are accepted.
+var lenientByteSizeUnits = map[string]ByteSize{
+	"k": KB, "m": MB, "g": GB, "t": TB, "p": PB, "e": EB,
+	"ki": KiB, "mi": MiB, "gi": GiB, "ti": TiB, "pi": PiB, "ei": EiB,
+}
+
+// The units that String() uses, largest first
+var byteSizeStringUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	{"EB", EB}, {"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"kB", KB},
+}
+
+// Parse a size like "512k", "1.5G", or "10MiB". If strict is true,
+// the unit must be spelled exactly, like "kB" or "MiB".
+func ParseByteSize(text string, strict bool) (ByteSize, error) {
+	// Split the number from the unit
+	i := strings.IndexFunc(text, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(text)
+	}
+	number := text[:i]
+	unitName := strings.TrimSpace(text[i:])
+	if number == "" {
+		return 0, fmt.Errorf("Cannot parse \"%s\" as a byte size", text)
+	}
+
+	unit, ok := byteSizeUnits[unitName]
+	if !ok && !strict {
+		lower := strings.ToLower(unitName)
+		unit, ok = lenientByteSizeUnits[strings.TrimSuffix(lower, "b")]
+		if !ok && lower == "b" {
+			unit, ok = Byte, true
+		}
+	}
+	if !ok {
+		return 0, fmt.Errorf("Unknown unit \"%s\" in byte size \"%s\"", unitName, text)
+	}
+
+	if !strings.Contains(number, ".") {
+		count, err := strconv.ParseUint(number, 10, 64)
+		if err != nil || count > math.MaxUint64/uint64(unit) {
+			return 0, fmt.Errorf("%s is out of range for a byte size", text)
+		}
+		return ByteSize(count) * unit, nil
+	}
+
+	// Parse the decimal exactly, so that 0.067G is 67000000 bytes
+	count, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("Cannot parse \"%s\" as a byte size", text)
+	}
+	size := count.Mul(count, new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(unit))))
+	if !size.IsInt() {
+		return 0, fmt.Errorf("%s is not a whole number of bytes", text)
+	}
+	if !size.Num().IsUint64() {
+		return 0, fmt.Errorf("%s is out of range for a byte size", text)
+	}
+	return ByteSize(size.Num().Uint64()), nil
+}
+
+// The size in the largest unit that it is a whole number of, like "10MiB"
+func (self ByteSize) String() string {
+	for _, unit := range byteSizeStringUnits {
+		if self != 0 && self%unit.size == 0 {
+			return strconv.FormatUint(uint64(self/unit.size), 10) + unit.name
+		}
+	}
+	return strconv.FormatUint(uint64(self), 10) + "B"
+}
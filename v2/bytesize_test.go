@@ -0,0 +1,153 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestParseByteSize(c *C) {
+	tests := []struct {
+		text     string
+		expected ByteSize
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"512k", 512 * KB},
+		{"512kB", 512 * KB},
+		{"1.5G", 1500 * MB},
+		{"0.067G", 67000000},
+		{"10MiB", 10 * MiB},
+		{"10mib", 10 * MiB},
+		{"2Gi", 2 * GiB},
+		{"1 TB", TB},
+	}
+
+	for _, test := range tests {
+		size, err := ParseByteSize(test.text, false)
+		c.Check(err, IsNil, Commentf("%s", test.text))
+		c.Check(size, Equals, test.expected, Commentf("%s", test.text))
+	}
+}
+
+func (s *MySuite) TestParseByteSizeStrict(c *C) {
+	size, err := ParseByteSize("10MiB", true)
+	c.Assert(err, IsNil)
+	c.Check(size, Equals, 10*MiB)
+
+	_, err = ParseByteSize("512k", true)
+	c.Check(err, ErrorMatches, `Unknown unit "k" in byte size "512k"`)
+
+	_, err = ParseByteSize("10mib", true)
+	c.Check(err, ErrorMatches, `Unknown unit "mib" in byte size "10mib"`)
+}
+
+func (s *MySuite) TestParseByteSizeErrors(c *C) {
+	_, err := ParseByteSize("MiB", false)
+	c.Check(err, ErrorMatches, `Cannot parse "MiB" as a byte size`)
+
+	_, err = ParseByteSize("1.2.3M", false)
+	c.Check(err, ErrorMatches, `Cannot parse "1.2.3M" as a byte size`)
+
+	for _, text := range []string{"16EiB", "18.5E"} {
+		_, err = ParseByteSize(text, false)
+		c.Check(err, ErrorMatches, text+" is out of range for a byte size")
+	}
+
+	_, err = ParseByteSize("1.5B", false)
+	c.Check(err, ErrorMatches, `1.5B is not a whole number of bytes`)
+
+	_, err = ParseByteSize("0.1KiB", false)
+	c.Check(err, ErrorMatches, `0.1KiB is not a whole number of bytes`)
+
+	_, err = ParseByteSize("10xB", false)
+	c.Check(err, ErrorMatches, `Unknown unit "xB" in byte size "10xB"`)
+}
+
+func (s *MySuite) TestByteSizeString(c *C) {
+	c.Check(ByteSize(0).String(), Equals, "0B")
+	c.Check(ByteSize(100).String(), Equals, "100B")
+	c.Check((10 * MiB).String(), Equals, "10MiB")
+	c.Check((1500 * MB).String(), Equals, "1500MB")
+	c.Check((3 * GB).String(), Equals, "3GB")
+	c.Check((1 * KiB).String(), Equals, "1KiB")
+}
+
+type SizeTestOptions struct {
+	Max     ByteSize
+	Min     *ByteSize
+	Chunk   ByteSize
+	Sizes   []ByteSize
+	Timeout time.Duration
+}
+
+func createSizeTestParser() (*SizeTestOptions, *ArgumentParser) {
+	opts := &SizeTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--max"},
+		Help:     "Largest",
+	})
+	ap.Add(&Argument{
+		Switches:       []string{"--min"},
+		StrictByteSize: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--chunk"},
+		Choices:  []ByteSize{4 * KiB, 64 * KiB},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--sizes"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--timeout"},
+		Help:     "Wait",
+		Choices:  []time.Duration{time.Hour, 7 * 24 * time.Hour},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestByteSizeArguments(c *C) {
+	opts, ap := createSizeTestParser()
+
+	argv := []string{"--max", "1.5G", "--min", "1KiB", "--chunk", "4096",
+		"--sizes", "1k", "--sizes", "2MiB", "--timeout", "1w"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Max, Equals, 1500*MB)
+	c.Assert(opts.Min, NotNil)
+	c.Check(*opts.Min, Equals, KiB)
+	c.Check(opts.Chunk, Equals, 4*KiB)
+	c.Check(opts.Sizes, DeepEquals, []ByteSize{KB, 2 * MiB})
+	c.Check(opts.Timeout, Equals, 7*24*time.Hour)
+}
+
+func (s *MySuite) TestByteSizeArgumentErrors(c *C) {
+	_, ap := createSizeTestParser()
+	results := ap.parseArgv([]string{"--min", "1k"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --min: Unknown unit "k" in byte size "1k"`)
+
+	_, ap = createSizeTestParser()
+	results = ap.parseArgv([]string{"--chunk", "8k"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --chunk: Not a valid choice. Should be one of: \[4KiB 64KiB\]`)
+}
+
+func (s *MySuite) TestByteSizeHelp(c *C) {
+	_, ap := createSizeTestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches,
+		`(?s).*--max=MAX +Largest \(a size, like 512k, 1.5G, or 10MiB\).*`)
+	c.Check(help, Matches,
+		`(?s).*--min=MIN +\(a size, like 512k, 1.5G, or 10MiB\).*`)
+	c.Check(help, Matches,
+		`(?s).*--timeout=TIMEOUT +Wait \(a duration, like 90s, 1h30m, or 7d\).*`)
+}
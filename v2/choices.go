@@ -0,0 +1,114 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements the forms that Choices can take, beyond a slice
+// of values: choices with descriptions, and choices computed at parse
+// time by a ChoicesFunc.
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// A valid value for an argument, with a description of it for the help.
+type Choice struct {
+	// The value, of the type of the destination field (or slice item),
+	// or a string.
+	Value interface{}
+
+	// The description of the value
+	Help string
+}
+
+// Set the choices for the value of this argument, from any of the forms
+// that Choices can take. The choices are also saved for the help.
+func (self *Argument) setChoices(m *Messages, choicesIntf interface{}) error {
+	values, descriptions, err := normalizeChoices(choicesIntf)
+	if err != nil {
+		return err
+	}
+	err = self.value.setChoices(m, values)
+	if err != nil {
+		return err
+	}
+	self.choiceValues = values
+	self.choiceHelp = descriptions
+	return nil
+}
+
+// Convert described choices, which are a []Choice or a map[string]string,
+// to a slice of the values, and a []Choice. A slice of values is returned
+// as-is, with descriptions that have no Help.
+func normalizeChoices(choicesIntf interface{}) (interface{}, []Choice, error) {
+	switch choices := choicesIntf.(type) {
+	case []Choice:
+		if len(choices) == 0 {
+			return []string{}, choices, nil
+		}
+		itemType := reflect.TypeOf(choices[0].Value)
+		if itemType == nil {
+			return nil, nil, errors.New("The Value of a Choice cannot be nil")
+		}
+		values := reflect.MakeSlice(reflect.SliceOf(itemType), 0, len(choices))
+		for _, choice := range choices {
+			if reflect.TypeOf(choice.Value) != itemType {
+				return nil, nil, fmt.Errorf("The Values of the Choices must all be of type %s",
+					itemType.String())
+			}
+			values = reflect.Append(values, reflect.ValueOf(choice.Value))
+		}
+		return values.Interface(), choices, nil
+
+	case map[string]string:
+		values := make([]string, 0, len(choices))
+		for value := range choices {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		descriptions := make([]Choice, len(values))
+		for i, value := range values {
+			descriptions[i] = Choice{Value: value, Help: choices[value]}
+		}
+		return values, descriptions, nil
+	}
+
+	choicesValue := reflect.ValueOf(choicesIntf)
+	if choicesValue.Kind() != reflect.Slice {
+		return choicesIntf, nil, nil
+	}
+	descriptions := make([]Choice, choicesValue.Len())
+	for i := range descriptions {
+		descriptions[i].Value = choicesValue.Index(i).Interface()
+	}
+	return choicesIntf, descriptions, nil
+}
+
+// Call the ChoicesFunc, once per parse, before the first value is parsed.
+func (self *Argument) loadChoices(m *Messages) error {
+	if self.ChoicesFunc == nil || self.choicesLoaded {
+		return nil
+	}
+	choices, err := self.ChoicesFunc()
+	if err != nil {
+		return fmt.Errorf(m.CannotGetChoicesFmt, err)
+	}
+	err = self.setChoices(m, choices)
+	if err != nil {
+		return fmt.Errorf(m.CannotGetChoicesFmt, err)
+	}
+	self.choicesLoaded = true
+	return nil
+}
+
+// Do the choices for the help have any descriptions?
+func hasChoiceHelp(choices []Choice) bool {
+	for _, choice := range choices {
+		if choice.Help != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+)
+
+type ChTestOptions struct {
+	Cluster string
+	Color   string
+	Level   int
+	Format  string
+}
+
+func createChTestParser(clusters []string, clusterErr error) (*ChTestOptions, *ArgumentParser, *int) {
+	opts := &ChTestOptions{}
+	calls := 0
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--cluster"},
+		Help:     "Where to deploy",
+		ChoicesFunc: func() (interface{}, error) {
+			calls++
+			return clusters, clusterErr
+		},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--color"},
+		Help:     "When to use colors",
+		Choices: map[string]string{
+			"auto":   "Use colors on a terminal",
+			"always": "Always use colors",
+			"never":  "Never use colors",
+		},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+		Choices: []Choice{
+			{Value: 1, Help: "Quiet"},
+			{Value: 2, Help: "Normal"},
+			{Value: 3, Help: "Chatty"},
+		},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--format"},
+		Help:     "Output format",
+		Choices:  []string{"json", "text"},
+	})
+	return opts, ap, &calls
+}
+
+func (s *MySuite) TestChoicesFunc(c *C) {
+	opts, ap, calls := createChTestParser([]string{"east", "west"}, nil)
+
+	// Not called if the argument is not given
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(*calls, Equals, 0)
+
+	opts, ap, calls = createChTestParser([]string{"east", "west"}, nil)
+	results = ap.parseArgv([]string{"--cluster", "west", "--cluster", "east"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Cluster, Equals, "east")
+	c.Check(*calls, Equals, 1)
+
+	_, ap, _ = createChTestParser([]string{"east", "west"}, nil)
+	results = ap.parseArgv([]string{"--cluster", "north"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --cluster: Not a valid choice. Should be one of: \[east west\]`)
+}
+
+// The ChoicesFunc is called again for each parse, and the help does not
+// keep the choices it gets
+func (s *MySuite) TestChoicesFuncEachParse(c *C) {
+	opts := &ChTestOptions{}
+	clusters := []string{"east"}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--cluster"},
+		ChoicesFunc: func() (interface{}, error) {
+			return clusters, nil
+		},
+	})
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--cluster=CLUSTER +\(one of: east\)\n.*`)
+
+	clusters = []string{"east", "west"}
+	results := ap.parseArgv([]string{"--cluster", "west"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Cluster, Equals, "west")
+
+	clusters = []string{"north"}
+	results = ap.parseArgv([]string{"--cluster", "west"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --cluster: Not a valid choice. Should be one of: \[north\]`)
+}
+
+func (s *MySuite) TestChoicesFuncError(c *C) {
+	_, ap, _ := createChTestParser(nil, errors.New("no config file"))
+
+	results := ap.parseArgv([]string{"--cluster", "east"})
+	c.Check(results.parseError, ErrorMatches,
+		"While parsing value for --cluster: Cannot get the choices: no config file")
+}
+
+func (s *MySuite) TestChoicesDescribed(c *C) {
+	opts, ap, _ := createChTestParser(nil, nil)
+
+	results := ap.parseArgv([]string{"--color", "never", "--level", "3"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Color, Equals, "never")
+	c.Check(opts.Level, Equals, 3)
+
+	_, ap, _ = createChTestParser(nil, nil)
+	results = ap.parseArgv([]string{"--color", "sometimes"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --color: Not a valid choice. Should be one of: \[always auto never\]`)
+
+	_, ap, _ = createChTestParser(nil, nil)
+	results = ap.parseArgv([]string{"--level", "4"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --level: Not a valid choice. Should be one of: \[1 2 3\]`)
+}
+
+func (s *MySuite) TestChoicesHelp(c *C) {
+	_, ap, _ := createChTestParser([]string{"east", "west"}, nil)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--cluster=CLUSTER +Where to deploy \(one of: east, west\)\n.*`)
+	c.Check(help, Matches, `(?s).*--format=FORMAT +Output format \(one of: json, text\)\n.*`)
+	c.Check(help, Matches, `(?s).*--color=COLOR +When to use colors\n`+
+		` +always +Always use colors\n`+
+		` +auto +Use colors on a terminal\n`+
+		` +never +Never use colors\n.*`)
+	c.Check(help, Matches, `(?s).*--level=LEVEL +\n +1 +Quiet\n +2 +Normal\n +3 +Chatty\n.*`)
+}
+
+// Messages translated before ChoicesHelpFmt was added get the English text
+func (s *MySuite) TestChoicesHelpOldMessages(c *C) {
+	_, ap, _ := createChTestParser(nil, nil)
+	ap.Messages = Messages{
+		SubCommands:     "Unterbefehle",
+		Options:         "Optionen",
+		HelpDescription: "Diese Liste der Optionen zeigen",
+	}
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*-h,--help +Diese Liste der Optionen zeigen\n.*`)
+	c.Check(help, Matches, `(?s).*--format=FORMAT +Output format \(one of: json, text\)\n.*`)
+	c.Check(help, Not(Matches), `(?s).*%!.*`)
+}
+
+func (s *MySuite) TestChoicesBadDefinitions(c *C) {
+	_, ap, _ := createChTestParser(nil, nil)
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--color2"},
+			Dest:     "Color",
+			Choices:  []string{"a"},
+			ChoicesFunc: func() (interface{}, error) {
+				return nil, nil
+			},
+		})
+	}, PanicMatches, "Argument --color2 cannot have both Choices and ChoicesFunc")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--level2"},
+			Dest:     "Level",
+			Choices:  []Choice{{Value: 1}, {Value: "2"}},
+		})
+	}, PanicMatches, "Argument --level2: The Values of the Choices must all be of type int")
+}
@@ -11,6 +11,16 @@ type Values interface{}
 
 type ParserCallback func(*Command, Values) error
 
+// Whether a Command allows long switches to be abbreviated
+type Abbreviations int
+
+const (
+	// Use the AllowAbbreviations setting of the ArgumentParser
+	AbbreviationsDefault Abbreviations = iota
+	AbbreviationsAllowed
+	AbbreviationsNotAllowed
+)
+
 type Command struct {
 	// The name of the program or subcommand
 	Name string
@@ -29,6 +39,16 @@ type Command struct {
 	// The function to call when this parser is selected
 	Function ParserCallback
 
+	// Override the AllowAbbreviations setting of the ArgumentParser
+	// for this Command.
+	AllowAbbreviations Abbreviations
+
+	// If set, a switch argument with no Env takes its value from an
+	// environment variable named by this prefix and its Dest, like
+	// MYTOOL_LOG_LEVEL for a prefix of "MYTOOL_" and a Dest of LogLevel.
+	// Sub-commands use the prefix of their parent if they have none.
+	EnvPrefix string
+
 	// Was an option seen during the parse? The key is the name
 	// of the destination variable.
 	Seen map[string]bool
@@ -40,6 +60,16 @@ type Command struct {
 	subCommands         []*Command
 	switchArguments     []*Argument
 	positionalArguments []*Argument
+	groups              []*ArgumentGroup
+
+	// The switch (or name) that the user gave for each argument seen
+	seenLabels map[*Argument]string
+
+	// The arguments whose last switch was a "--no-" switch
+	negated map[*Argument]bool
+
+	// Where the value of each argument seen came from, by Dest
+	sources map[string]ValueSource
 
 	numRequiredPositionalArguments int
 	// -1 if there is no max (i.e., if the final NumArgsGlob is "*" or "+")
@@ -47,12 +77,15 @@ type Command struct {
 
 	// Pointer to the ArgumentParser
 	ap *ArgumentParser
+
+	// The parent Command, or nil for the root Command
+	parent *Command
 }
 
 func (self *Command) init(parent *Command, ap *ArgumentParser) {
-	self.Seen = make(map[string]bool)
-	self.CommandSeen = make(map[string]bool)
+	self.clearSeen()
 	self.ap = ap
+	self.parent = parent
 
 	// Nothing futher for the root Command
 	if parent == nil {
@@ -68,6 +101,42 @@ func (self *Command) init(parent *Command, ap *ArgumentParser) {
 	}
 }
 
+// Forget which arguments and sub-commands were seen, and where their
+// values came from
+func (self *Command) clearSeen() {
+	self.Seen = make(map[string]bool)
+	self.CommandSeen = make(map[string]bool)
+	self.seenLabels = make(map[*Argument]string)
+	self.negated = make(map[*Argument]bool)
+	self.sources = make(map[string]ValueSource)
+}
+
+// Forget what was learned during the last parse, for this Command and
+// its sub-commands
+func (self *Command) resetParse() {
+	self.clearSeen()
+	for _, args := range [][]*Argument{self.switchArguments, self.positionalArguments} {
+		for _, arg := range args {
+			arg.resetParse()
+		}
+	}
+	for _, subCommand := range self.subCommands {
+		subCommand.resetParse()
+	}
+}
+
+// Can the long switches of this Command be abbreviated?
+func (self *Command) abbreviationsAllowed() bool {
+	switch self.AllowAbbreviations {
+	case AbbreviationsAllowed:
+		return true
+	case AbbreviationsNotAllowed:
+		return false
+	default:
+		return self.ap.AllowAbbreviations
+	}
+}
+
 func (self *Command) propagateInherited(cmds []*Command, myIndex int) {
 	if self != cmds[myIndex] {
 		panic(fmt.Sprintf("Expected %v at %d but got %v", self, myIndex,
@@ -88,14 +157,23 @@ func (self *Command) propagateInherited(cmds []*Command, myIndex int) {
 	}
 
 	for _, arg := range self.switchArguments {
-		if arg.Inherit && self.Seen[arg.Dest] && !nextCmd.Seen[arg.Dest] {
+		if arg.Inherit && self.seenLabels[arg] != "" && !nextCmd.Seen[arg.Dest] {
 			// Propagate
 			found := false
 			for _, nextCmdArg := range nextCmd.switchArguments {
-				if nextCmdArg.Dest == arg.Dest {
+				// Several arguments can share a Dest, so make sure
+				// this is the copy of arg.
+				if nextCmdArg.Dest == arg.Dest && nextCmdArg.Switches[0] == arg.Switches[0] {
 					found = true
 					nextCmdArg.value.setValue(arg.value.getValue())
 					nextCmd.Seen[arg.Dest] = true
+					nextCmd.seenLabels[nextCmdArg] = self.seenLabels[arg]
+					nextCmd.negated[nextCmdArg] = self.negated[arg]
+					source := self.sources[arg.Dest].copy()
+					if source.InheritedFrom == "" {
+						source.InheritedFrom = self.Name
+					}
+					nextCmd.setSource(nextCmdArg, source)
 					break
 				}
 			}
@@ -103,6 +181,13 @@ func (self *Command) propagateInherited(cmds []*Command, myIndex int) {
 				panic(fmt.Sprintf("Arg %s inherited from %s to %s can't be found",
 					arg.Dest, self.Name, nextCmd.Name))
 			}
+		} else if arg.Inherit && self.seenLabels[arg] != "" &&
+			nextCmd.sources[arg.Dest].InheritedFrom != "" {
+			// It was propagated before; bring along the places that
+			// were shadowed since then
+			for _, shadowed := range self.sources[arg.Dest].Shadowed {
+				nextCmd.addShadowedSource(arg, shadowed)
+			}
 		}
 	}
 
@@ -111,6 +196,59 @@ func (self *Command) propagateInherited(cmds []*Command, myIndex int) {
 	}
 }
 
+// Find an argument of this Command by one of its switches, its Name,
+// or its Dest. Returns nil if there is no such argument.
+func (self *Command) findArgument(ref string) *Argument {
+	for _, arg := range self.switchArguments {
+		for _, switchName := range arg.Switches {
+			if switchName == ref {
+				return arg
+			}
+		}
+	}
+	for _, arg := range self.positionalArguments {
+		if arg.Name == ref {
+			return arg
+		}
+	}
+	for _, arg := range self.switchArguments {
+		if arg.Dest == ref {
+			return arg
+		}
+	}
+	for _, arg := range self.positionalArguments {
+		if arg.Dest == ref {
+			return arg
+		}
+	}
+	return nil
+}
+
+// Find the arguments named by the Requires, ConflictsWith, or RequiredIf
+// field of arg.
+func (self *Command) findReferencedArguments(arg *Argument, fieldName string, refs []string) []*Argument {
+	if len(refs) == 0 {
+		return nil
+	}
+	others := make([]*Argument, len(refs))
+	for i, ref := range refs {
+		other := self.findArgument(ref)
+		if other == nil {
+			panic(fmt.Sprintf("Argument %s: %s refers to %s, which is not an "+
+				"argument in Command %s", arg.PrettyName(), fieldName, ref, self.Name))
+		}
+		// The sub-commands that inherit arg must be able to find
+		// the other argument too.
+		if arg.Inherit && !other.Inherit {
+			panic(fmt.Sprintf("Argument %s: %s refers to %s, which is not "+
+				"inherited, but %s is", arg.PrettyName(), fieldName, ref,
+				arg.PrettyName()))
+		}
+		others[i] = other
+	}
+	return others
+}
+
 func (self *Command) New(cmd *Command) *Command {
 
 	// Check for duplicates
@@ -148,8 +286,8 @@ func (self *Command) Add(arg *Argument) {
 		}
 	} else {
 		for _, other := range self.switchArguments {
-			for _, otherSwitch := range other.Switches {
-				for _, thisSwitch := range arg.Switches {
+			for _, otherSwitch := range append(other.negatedSwitches(), other.Switches...) {
+				for _, thisSwitch := range append(arg.negatedSwitches(), arg.Switches...) {
 					if otherSwitch == thisSwitch {
 						panic(fmt.Sprintf("%s is already used by a "+
 							"switch argument in this Command.", thisSwitch))
@@ -166,6 +304,23 @@ func (self *Command) Add(arg *Argument) {
 
 	// set arg.value
 	arg.init(self.Values, &self.ap.Messages)
+	arg.envName = self.envName(arg)
+	// The arguments that share the Dest no longer get a name made from
+	// the EnvPrefix
+	for _, other := range self.switchArguments {
+		if other.Dest == arg.Dest {
+			other.envName = other.Env
+		}
+	}
+
+	// The arguments that this one refers to must already be in this Command
+	arg.requiresArgs = self.findReferencedArguments(arg, "Requires", arg.Requires)
+	arg.conflictsWithArgs = self.findReferencedArguments(arg, "ConflictsWith", arg.ConflictsWith)
+	conditionRefs := make([]string, len(arg.RequiredIf))
+	for i, condition := range arg.RequiredIf {
+		conditionRefs[i] = condition.Argument
+	}
+	arg.requiredIfArgs = self.findReferencedArguments(arg, "RequiredIf", conditionRefs)
 
 	if arg.isPositional() {
 		if len(self.positionalArguments) > 0 {
@@ -213,10 +368,10 @@ func (self *Command) Add(arg *Argument) {
 
 		// If the positional argument accepts more than one value,
 		// the destination must be a slice
-		if arg.NumArgs == -1 && canBeMoreThanOne && arg.value.storageType() != Slice {
+		if arg.NumArgs == -1 && canBeMoreThanOne && !arg.value.storageType().holdsMany() {
 			panic(fmt.Sprintf(
 				"Cannot use positional argument %s with a non-slice destination variable because NumArgsGlob is %s", arg.PrettyName(), arg.NumArgsGlob))
-		} else if arg.NumArgs > 1 && arg.value.storageType() != Slice {
+		} else if arg.NumArgs > 1 && !arg.value.storageType().holdsMany() {
 			panic(fmt.Sprintf(
 				"Cannot use positional argument %s with a non-slice destination variable because NumArgs is %d", arg.PrettyName(), arg.NumArgs))
 		}
@@ -232,7 +387,7 @@ func (self *Command) Add(arg *Argument) {
 		}
 		// If the switch argument accepts more than one value,
 		// the destination must be a slice
-		if arg.NumArgs > 1 && arg.value.storageType() != Slice {
+		if arg.NumArgs > 1 && !arg.value.storageType().holdsMany() {
 			panic(fmt.Sprintf(
 				"Cannot use switch argument %s with a non-slice destination variable because NumArgs is %d", arg.PrettyName(), arg.NumArgs))
 		}
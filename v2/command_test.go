@@ -142,3 +142,92 @@ func (s *MySuite) TestSubCommandSubAPost(c *C) {
 	c.Check(suba.Seen["Verbose"], Equals, true)
 	c.Check(suba.Seen["String2"], Equals, true)
 }
+
+func createCTestRequiredParser() (*CTestOptions, *ArgumentParser) {
+	opts := &CTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      &opts.root,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--string1"},
+		Inherit:  true,
+		Required: true,
+	})
+	ap.New(&Command{
+		Name:   "sub-a",
+		Values: &opts.a,
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestSubCommandRequiredInheritedPre(c *C) {
+	opts, ap := createCTestRequiredParser()
+
+	results := ap.parseArgv([]string{"--string1", "x", "sub-a"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.a.String1, Equals, "x")
+}
+
+func (s *MySuite) TestSubCommandRequiredInheritedPost(c *C) {
+	opts, ap := createCTestRequiredParser()
+
+	results := ap.parseArgv([]string{"sub-a", "--string1", "y"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.a.String1, Equals, "y")
+}
+
+func (s *MySuite) TestSubCommandRequiredInheritedMissing(c *C) {
+	_, ap := createCTestRequiredParser()
+
+	results := ap.parseArgv([]string{"sub-a"})
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --string1")
+}
+
+func (s *MySuite) TestSubCommandRequiredAtRoot(c *C) {
+	_, ap, _, _ := createCTestParser()
+	ap.Root.switchArguments[1].Required = true
+
+	results := ap.parseArgv([]string{"sub-a"})
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --debug/-d")
+
+	opts, ap, _, _ := createCTestParser()
+	ap.Root.switchArguments[1].Required = true
+
+	results = ap.parseArgv([]string{"-d", "sub-a"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.root.Debug, Equals, true)
+}
+
+func (s *MySuite) TestSubCommandNegatableInherited(c *C) {
+	opts := &CTestOptions{}
+	opts.root.Verbose = true
+	opts.a.Verbose = true
+	opts.b.Verbose = true
+	ap := New(&Command{
+		Values: &opts.root,
+	})
+	ap.Add(&Argument{
+		Switches:  []string{"--verbose"},
+		Inherit:   true,
+		Negatable: true,
+	})
+	ap.New(&Command{
+		Name:   "sub-a",
+		Values: &opts.a,
+	})
+	ap.New(&Command{
+		Name:   "sub-b",
+		Values: &opts.b,
+	})
+
+	// Given after the sub-command
+	results := ap.parseArgv([]string{"sub-a", "--no-verbose"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.a.Verbose, Equals, false)
+
+	// Given before the sub-command
+	results = ap.parseArgv([]string{"--no-verbose", "sub-b"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.b.Verbose, Equals, false)
+}
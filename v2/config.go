@@ -0,0 +1,270 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements taking the values of switch arguments from a
+// configuration file, when they are not given on the command-line or in
+// the environment.
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// The format of a configuration file
+type ConfigFormat int
+
+const (
+	// Choose the format by the extension of the file: ".json" for JSON,
+	// ".toml" for TOML, and anything else for INI.
+	ConfigFormatAuto ConfigFormat = iota
+	ConfigFormatJSON
+	ConfigFormatTOML
+	ConfigFormatINI
+)
+
+// A key and its values, as read from a configuration file
+type configEntry struct {
+	// The section names and the key, like ["sub", "log-level"]
+	path []string
+
+	// The values, as text
+	values []string
+
+	// Were the values given as a list, like [1, 2, 3]?
+	list bool
+
+	// The line of the file where the key is
+	line int
+}
+
+// The key as it is written in the file, with its sections
+func (self *configEntry) key() string {
+	return strings.Join(self.path, ".")
+}
+
+// Add the file name and line number to an error
+func fileLineError(filename string, line int, err error) error {
+	return fmt.Errorf("%s:%d: %w", filename, line, err)
+}
+
+// The line number, starting at 1, of an offset in the data
+func lineAtOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// The text of a line, starting at 1, without the surrounding space
+func lineText(text string, line int) string {
+	lines := strings.Split(text, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// Find the configuration file to read: the value of the ConfigArgument,
+// if it was given, or else the first of the ConfigFiles that exists.
+// Returns "" if there is none.
+func (self *ArgumentParser) configFilename(cmdStack []*Command) (string, error) {
+	if self.ConfigArgument != "" {
+		arg := self.Root.findArgument(self.ConfigArgument)
+		if arg == nil {
+			panic(fmt.Sprintf("ConfigArgument %s is not an argument of the "+
+				"root Command", self.ConfigArgument))
+		}
+		if arg.value.getValue().Kind() != reflect.String {
+			panic(fmt.Sprintf("ConfigArgument %s must be a string", self.ConfigArgument))
+		}
+		// It may have been given to a sub-command that inherited it
+		for i := len(cmdStack) - 1; i >= 0; i-- {
+			arg = cmdStack[i].findArgument(self.ConfigArgument)
+			if arg != nil && cmdStack[i].seenLabels[arg] != "" {
+				return arg.value.getValue().String(), nil
+			}
+		}
+	}
+
+	for _, filename := range self.ConfigFiles {
+		_, err := os.Stat(filename)
+		if err == nil {
+			return filename, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf(self.Messages.CannotReadConfigFmt, err)
+		}
+	}
+	return "", nil
+}
+
+// Read the entries of the configuration file
+func (self *ArgumentParser) readConfig(filename string) ([]*configEntry, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(self.Messages.CannotReadConfigFmt, err)
+	}
+
+	format := self.ConfigFormat
+	if format == ConfigFormatAuto {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".json":
+			format = ConfigFormatJSON
+		case ".toml":
+			format = ConfigFormatTOML
+		default:
+			format = ConfigFormatINI
+		}
+	}
+
+	switch format {
+	case ConfigFormatJSON:
+		return parseJSONConfig(&self.Messages, filename, data)
+	case ConfigFormatTOML:
+		return parseTOMLConfig(&self.Messages, filename, string(data))
+	case ConfigFormatINI:
+		return parseINIConfig(&self.Messages, filename, string(data))
+	default:
+		panic(fmt.Sprintf("Unknown ConfigFormat %d", format))
+	}
+}
+
+// For each argument of the Commands that was not seen on the command-line
+// or in the environment, take its value from the configuration file, if
+// there is one. Every key in the file must be an argument, even if its
+// Command was not triggered.
+func (self *ArgumentParser) applyConfig(cmdStack []*Command) error {
+	m := &self.Messages
+	filename, err := self.configFilename(cmdStack)
+	if err != nil || filename == "" {
+		return err
+	}
+	entries, err := self.readConfig(filename)
+	if err != nil {
+		return err
+	}
+
+	// Find the argument of each entry
+	found := make(map[*Argument][]*configEntry)
+	for _, entry := range entries {
+		arg, mapKey := self.Root.findConfigArgument(entry.path)
+		if arg == nil {
+			return fileLineError(filename, entry.line,
+				fmt.Errorf(m.UnknownConfigKeyFmt, entry.key()))
+		}
+		holdsMany := arg.value.storageType().holdsMany()
+		if entry.list && (!holdsMany || mapKey != "") {
+			return fileLineError(filename, entry.line,
+				fmt.Errorf(m.ConfigNotAListFmt, entry.key()))
+		}
+		if len(found[arg]) > 0 && !holdsMany {
+			return fileLineError(filename, entry.line,
+				fmt.Errorf(m.DuplicateConfigKeyFmt, entry.key()))
+		}
+		if mapKey != "" {
+			separator := arg.value.(*mapValueT).separator
+			entry.values[0] = mapKey + separator + entry.values[0]
+		}
+		found[arg] = append(found[arg], entry)
+	}
+
+	for _, cmd := range cmdStack {
+		for _, arg := range cmd.switchArguments {
+			argEntries := found[arg]
+			if len(argEntries) == 0 {
+				continue
+			}
+			source := ValueSource{
+				Kind:  SourceConfigFile,
+				Label: argEntries[0].key(),
+				File:  filename,
+				Line:  argEntries[0].line,
+			}
+			if cmd.seenLabels[arg] != "" || cmd.Seen[arg.Dest] {
+				cmd.addShadowedSource(arg, source)
+				continue
+			}
+			// The values from the file replace the slice
+			if arg.Action == ActionReplace {
+				slice := arg.value.getValue()
+				slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+			}
+			anyGiven := false
+			for _, entry := range argEntries {
+				for _, text := range entry.values {
+					given, err := arg.storeExternalValue(m, entry.key(), text)
+					if err != nil {
+						return fileLineError(filename, entry.line, err)
+					}
+					anyGiven = anyGiven || given
+				}
+			}
+			if anyGiven {
+				cmd.Seen[arg.Dest] = true
+				cmd.seenLabels[arg] = source.Label
+				cmd.setSource(arg, source)
+			}
+		}
+	}
+	return nil
+}
+
+// Find the argument for the path of a configuration key. The leading
+// names are sub-commands, and the rest is the key of a switch argument
+// of the last sub-command, or the key of a map argument and then a key of
+// the map. Returns the argument and the key of the map, if any, or nil
+// if there is no such argument.
+func (self *Command) findConfigArgument(path []string) (*Argument, string) {
+	cmd := self
+	i := 0
+	for ; i < len(path)-1; i++ {
+		var subCommand *Command
+		for _, other := range cmd.subCommands {
+			if other.Name == path[i] {
+				subCommand = other
+				break
+			}
+		}
+		if subCommand == nil {
+			break
+		}
+		cmd = subCommand
+	}
+	rest := path[i:]
+
+	arg := cmd.findConfigKey(strings.Join(rest, "."))
+	if arg != nil {
+		return arg, ""
+	}
+	if len(rest) > 1 {
+		arg = cmd.findConfigKey(strings.Join(rest[:len(rest)-1], "."))
+		if arg != nil && arg.value.storageType() == Map {
+			return arg, rest[len(rest)-1]
+		}
+	}
+	return nil, ""
+}
+
+// Find the switch argument for a configuration key, which is either its
+// Dest, or one of its long switches without the "--". An "_" in the key
+// can be used for a "-" in the switch.
+func (self *Command) findConfigKey(key string) *Argument {
+	switchName := "--" + strings.Replace(key, "_", "-", -1)
+	for _, arg := range self.switchArguments {
+		if arg.Dest == key {
+			return arg
+		}
+		for _, s := range arg.Switches {
+			if s == "--"+key || s == switchName {
+				return arg
+			}
+		}
+	}
+	return nil
+}
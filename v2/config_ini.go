@@ -0,0 +1,80 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Reads an INI configuration file. Each line is a [section], a
+// "key = value" or "key: value", or a comment starting with "#" or ";".
+// Values can be quoted. A key can be repeated to give several values for
+// a slice or map argument.
+func parseINIConfig(m *Messages, filename string, text string) ([]*configEntry, error) {
+	entries := []*configEntry{}
+	section := []string{}
+
+	for i, content := range strings.Split(text, "\n") {
+		line := i + 1
+		content = strings.TrimSpace(content)
+		if content == "" || content[0] == '#' || content[0] == ';' {
+			continue
+		}
+
+		syntaxError := fileLineError(filename, line,
+			fmt.Errorf(m.ConfigSyntaxErrorFmt, content))
+
+		if content[0] == '[' {
+			if !strings.HasSuffix(content, "]") {
+				return nil, syntaxError
+			}
+			names := splitConfigKey(content[1 : len(content)-1])
+			if names == nil {
+				return nil, syntaxError
+			}
+			section = names
+			continue
+		}
+
+		sep := strings.IndexAny(content, "=:")
+		if sep == -1 {
+			return nil, syntaxError
+		}
+		keys := splitConfigKey(content[:sep])
+		if keys == nil {
+			return nil, syntaxError
+		}
+		value := strings.TrimSpace(content[sep+1:])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, syntaxError
+			}
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+
+		entries = append(entries, &configEntry{
+			path:   append(append([]string{}, section...), keys...),
+			values: []string{value},
+			line:   line,
+		})
+	}
+	return entries, nil
+}
+
+// Split a dotted key or section name into its parts. Returns nil if a
+// part is empty.
+func splitConfigKey(text string) []string {
+	parts := strings.Split(text, ".")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if parts[i] == "" {
+			return nil
+		}
+	}
+	return parts
+}
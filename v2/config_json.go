@@ -0,0 +1,155 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Reads a JSON configuration file. The file is an object; an object in it
+// is a section for a sub-command, a nested struct, or a map argument.
+type jsonConfigParser struct {
+	m        *Messages
+	filename string
+	data     []byte
+	decoder  *json.Decoder
+	entries  []*configEntry
+}
+
+func parseJSONConfig(m *Messages, filename string, data []byte) ([]*configEntry, error) {
+	self := &jsonConfigParser{
+		m:        m,
+		filename: filename,
+		data:     data,
+		decoder:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	// Keep the numbers as they are written
+	self.decoder.UseNumber()
+
+	token, err := self.decoder.Token()
+	if err != nil {
+		return nil, self.syntaxError(err)
+	}
+	if token != json.Delim('{') {
+		return nil, self.errorAtLine(self.line())
+	}
+	err = self.parseObject(nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = self.decoder.Token()
+	if err != io.EOF {
+		if err != nil {
+			return nil, self.syntaxError(err)
+		}
+		return nil, self.errorAtLine(self.line())
+	}
+	return self.entries, nil
+}
+
+// The line that the decoder has read up to
+func (self *jsonConfigParser) line() int {
+	return lineAtOffset(self.data, self.decoder.InputOffset())
+}
+
+// An error for the line, showing the line
+func (self *jsonConfigParser) errorAtLine(line int) error {
+	return fileLineError(self.filename, line,
+		fmt.Errorf(self.m.ConfigSyntaxErrorFmt, lineText(string(self.data), line)))
+}
+
+// An error from the decoder
+func (self *jsonConfigParser) syntaxError(err error) error {
+	line := lineAtOffset(self.data, int64(len(self.data)))
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		line = lineAtOffset(self.data, syntaxErr.Offset)
+	}
+	return fileLineError(self.filename, line, fmt.Errorf(self.m.ConfigSyntaxErrorFmt, err))
+}
+
+// Parse the members of an object, after its "{"
+func (self *jsonConfigParser) parseObject(path []string) error {
+	for {
+		token, err := self.decoder.Token()
+		if err != nil {
+			return self.syntaxError(err)
+		}
+		if token == json.Delim('}') {
+			return nil
+		}
+		key := token.(string)
+		line := self.line()
+		keyPath := append(append([]string{}, path...), key)
+
+		token, err = self.decoder.Token()
+		if err != nil {
+			return self.syntaxError(err)
+		}
+		switch token {
+		case json.Delim('{'):
+			err = self.parseObject(keyPath)
+			if err != nil {
+				return err
+			}
+		case json.Delim('['):
+			values, err := self.parseArray()
+			if err != nil {
+				return err
+			}
+			self.entries = append(self.entries, &configEntry{
+				path:   keyPath,
+				values: values,
+				list:   true,
+				line:   line,
+			})
+		case nil:
+			// A null leaves the argument unset
+		default:
+			self.entries = append(self.entries, &configEntry{
+				path:   keyPath,
+				values: []string{jsonText(token)},
+				line:   line,
+			})
+		}
+	}
+}
+
+// Parse the items of an array, after its "[". The items cannot be arrays
+// or objects.
+func (self *jsonConfigParser) parseArray() ([]string, error) {
+	values := []string{}
+	for {
+		token, err := self.decoder.Token()
+		if err != nil {
+			return nil, self.syntaxError(err)
+		}
+		switch token {
+		case json.Delim(']'):
+			return values, nil
+		case json.Delim('{'), json.Delim('['):
+			return nil, self.errorAtLine(self.line())
+		case nil:
+			// Skip a null
+		default:
+			values = append(values, jsonText(token))
+		}
+	}
+}
+
+// The text of a JSON string, number, or boolean
+func jsonText(token json.Token) string {
+	switch value := token.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		panic(fmt.Sprintf("Unexpected JSON token %v", token))
+	}
+}
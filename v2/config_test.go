@@ -0,0 +1,276 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type ConfigTestOptions struct {
+	Config   string
+	LogLevel string
+	Workers  int
+	Verbose  bool
+	Quiet    int
+	Names    []string
+	Labels   map[string]string
+	Timeout  time.Duration
+	DB       struct {
+		MaxConns int
+	}
+}
+
+type ConfigTestRunOptions struct {
+	Config   string
+	LogLevel string
+	Force    bool
+}
+
+func createConfigTestParser() (*ConfigTestOptions, *ConfigTestRunOptions, *ArgumentParser) {
+	opts := &ConfigTestOptions{}
+	runOpts := &ConfigTestRunOptions{}
+	ap := New(&Command{
+		Values:    opts,
+		EnvPrefix: "TOOL_",
+	})
+	ap.ConfigArgument = "--config"
+	ap.LookupEnv = func(name string) (string, bool) {
+		return "", false
+	}
+	ap.Add(&Argument{
+		Switches: []string{"--config"},
+		Inherit:  true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--log-level"},
+		Choices:  []string{"debug", "info", "warn"},
+		Inherit:  true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--workers"},
+		Required: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v", "--verbose"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-q"},
+		Dest:     "Quiet",
+		Action:   ActionCount,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--names"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--label"},
+		Dest:     "Labels",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--timeout"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.max-conns"},
+	})
+	run := ap.New(&Command{
+		Name:   "run",
+		Values: runOpts,
+	})
+	run.Add(&Argument{
+		Switches: []string{"--force"},
+	})
+	return opts, runOpts, ap
+}
+
+func writeConfigFile(c *C, name string, text string) string {
+	filename := filepath.Join(c.MkDir(), name)
+	err := ioutil.WriteFile(filename, []byte(text), 0644)
+	c.Assert(err, IsNil)
+	return filename
+}
+
+func (s *MySuite) TestConfigTOML(c *C) {
+	filename := writeConfigFile(c, "tool.toml", `
+# The settings
+log-level = "debug"
+workers = 4
+verbose = true
+Quiet = true
+names = [
+    "a", 'b',  # a comment
+    "c",
+]
+label = { app = "web", "tier.name" = "front" }
+timeout = "1m30s"
+
+[db]
+max_conns = 1_000
+
+[run]
+force = true
+`)
+	opts, runOpts, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"--config", filename})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, "debug")
+	c.Check(opts.Workers, Equals, 4)
+	c.Check(opts.Verbose, Equals, true)
+	c.Check(opts.Quiet, Equals, 1)
+	c.Check(opts.Names, DeepEquals, []string{"a", "b", "c"})
+	c.Check(opts.Labels, DeepEquals, map[string]string{"app": "web", "tier.name": "front"})
+	c.Check(opts.Timeout, Equals, 90*time.Second)
+	c.Check(opts.DB.MaxConns, Equals, 1000)
+	c.Check(ap.Root.Seen["Workers"], Equals, true)
+	c.Check(runOpts.Force, Equals, false)
+
+	// The run section is used when the run sub-command is given, and
+	// the inherited values from the root section are propagated
+	opts, runOpts, ap = createConfigTestParser()
+	results = ap.parseArgv([]string{"--config", filename, "run"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(runOpts.Force, Equals, true)
+	c.Check(runOpts.LogLevel, Equals, "debug")
+	c.Check(results.triggeredCommand.Seen["LogLevel"], Equals, true)
+}
+
+// The --config of the last parse is not used again
+func (s *MySuite) TestConfigParseTwice(c *C) {
+	filename := writeConfigFile(c, "tool.ini", "workers = 4\n")
+	opts, _, ap := createConfigTestParser()
+
+	results := ap.parseArgv([]string{"--config", filename})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Workers, Equals, 4)
+
+	results = ap.parseArgv([]string{})
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --workers")
+}
+
+func (s *MySuite) TestConfigJSON(c *C) {
+	filename := writeConfigFile(c, "tool.json", `{
+    "LogLevel": "info",
+    "workers": 8,
+    "names": ["x", "y"],
+    "label": {"app": "db"},
+    "db": {"max-conns": 10},
+    "timeout": null,
+    "run": {"force": true, "log-level": "warn"}
+}`)
+	opts, runOpts, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"run", "--config", filename})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, "info")
+	c.Check(opts.Workers, Equals, 8)
+	c.Check(opts.Names, DeepEquals, []string{"x", "y"})
+	c.Check(opts.Labels, DeepEquals, map[string]string{"app": "db"})
+	c.Check(opts.DB.MaxConns, Equals, 10)
+	c.Check(opts.Timeout, Equals, time.Duration(0))
+	c.Check(runOpts.Force, Equals, true)
+	// The run section overrides the inherited value of the root section
+	c.Check(runOpts.LogLevel, Equals, "warn")
+}
+
+func (s *MySuite) TestConfigINI(c *C) {
+	filename := writeConfigFile(c, "tool.conf", `
+; The settings
+workers = 2
+names = one
+names: "two words"
+label = app=api
+
+[run]
+force = 1
+`)
+	opts, runOpts, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"--config", filename, "run"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Workers, Equals, 2)
+	c.Check(opts.Names, DeepEquals, []string{"one", "two words"})
+	c.Check(opts.Labels, DeepEquals, map[string]string{"app": "api"})
+	c.Check(runOpts.Force, Equals, true)
+}
+
+func (s *MySuite) TestConfigPrecedence(c *C) {
+	filename := writeConfigFile(c, "tool.toml", `
+log-level = "debug"
+workers = 4
+verbose = true
+`)
+	opts, _, ap := createConfigTestParser()
+	ap.LookupEnv = func(name string) (string, bool) {
+		if name == "TOOL_WORKERS" {
+			return "6", true
+		}
+		return "", false
+	}
+	results := ap.parseArgv([]string{"--config", filename, "--log-level", "warn"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, "warn")
+	c.Check(opts.Workers, Equals, 6)
+	c.Check(opts.Verbose, Equals, true)
+}
+
+func (s *MySuite) TestConfigFiles(c *C) {
+	dir := c.MkDir()
+	filename := filepath.Join(dir, "tool.ini")
+	err := ioutil.WriteFile(filename, []byte("workers = 3\n"), 0644)
+	c.Assert(err, IsNil)
+
+	opts, _, ap := createConfigTestParser()
+	ap.ConfigFiles = []string{filepath.Join(dir, "missing.ini"), filename}
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Workers, Equals, 3)
+
+	// The ConfigArgument must name a file that exists
+	_, _, ap = createConfigTestParser()
+	ap.ConfigFiles = []string{filename}
+	results = ap.parseArgv([]string{"--config", filepath.Join(dir, "missing.ini")})
+	c.Check(results.parseError, ErrorMatches,
+		"Cannot read the configuration file: open .*missing.ini: no such file or directory")
+
+	// No configuration file is fine
+	_, _, ap = createConfigTestParser()
+	ap.ConfigFiles = []string{filepath.Join(dir, "missing.ini")}
+	results = ap.parseArgv([]string{"--workers", "1"})
+	c.Check(results.parseError, IsNil)
+}
+
+func (s *MySuite) TestConfigErrors(c *C) {
+	tests := []struct {
+		name  string
+		text  string
+		error string
+	}{
+		{"unknown.toml", "workers = 1\n\ncolor = \"red\"\n",
+			`.*unknown.toml:3: Unknown key "color"`},
+		{"unknown.json", "{\n  \"workers\": 1,\n  \"run\": {\"color\": 1}\n}",
+			`.*unknown.json:3: Unknown key "run.color"`},
+		{"type.ini", "workers = many\n",
+			`.*type.ini:1: While parsing value for workers: Cannot convert "many" to an integer: .*`},
+		{"choice.toml", "workers = 1\nlog-level = \"loud\"\n",
+			`.*choice.toml:2: While parsing value for log-level: Not a valid choice. Should be one of: \[debug info warn\]`},
+		{"list.toml", "workers = [1, 2]\n",
+			`.*list.toml:1: workers takes a single value, not a list`},
+		{"dup.ini", "workers = 1\nWorkers = 2\n",
+			`.*dup.ini:2: The key "Workers" is given more than once`},
+		{"syntax.toml", "workers = 1\nnames = [\"a\" \"b\"]\n",
+			`.*syntax.toml:2: Cannot parse: names = \["a" "b"\]`},
+		{"string.toml", "workers = 1\nlog-level = \"debug\n",
+			`.*string.toml:2: Cannot parse: log-level = "debug`},
+		{"syntax.ini", "workers = 1\n[run\n",
+			`.*syntax.ini:2: Cannot parse: \[run`},
+		{"syntax.json", "{\n  \"workers\": 1,\n  \"names\": [1,]\n}",
+			`.*syntax.json:3: Cannot parse: invalid character .* looking for beginning of value`},
+	}
+
+	for _, test := range tests {
+		filename := writeConfigFile(c, test.name, test.text)
+		_, _, ap := createConfigTestParser()
+		results := ap.parseArgv([]string{"--config", filename})
+		c.Check(results.parseError, ErrorMatches, test.error, Commentf(test.name))
+	}
+}
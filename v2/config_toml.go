@@ -0,0 +1,325 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Reads a TOML configuration file. This handles the subset of TOML
+// that configuration needs: tables, dotted keys, basic and literal
+// strings, bare values like numbers, booleans, and dates, arrays of those,
+// and inline tables. Multi-line strings and arrays of tables are not
+// supported.
+type tomlConfigParser struct {
+	m        *Messages
+	filename string
+	text     string
+	pos      int
+	line     int
+	section  []string
+	entries  []*configEntry
+}
+
+func parseTOMLConfig(m *Messages, filename string, text string) ([]*configEntry, error) {
+	self := &tomlConfigParser{
+		m:        m,
+		filename: filename,
+		text:     text,
+		line:     1,
+	}
+
+	for {
+		self.skipSpace()
+		if self.pos == len(self.text) {
+			return self.entries, nil
+		}
+		var err error
+		switch self.text[self.pos] {
+		case '\n':
+			self.pos++
+			self.line++
+		case '#':
+			self.skipComment()
+		case '[':
+			err = self.parseTable()
+		default:
+			err = self.parseKeyValue(self.section)
+			if err == nil {
+				err = self.endOfLine()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// An error for the current line, showing the line
+func (self *tomlConfigParser) syntaxError() error {
+	return fileLineError(self.filename, self.line,
+		fmt.Errorf(self.m.ConfigSyntaxErrorFmt, lineText(self.text, self.line)))
+}
+
+// Skip spaces and tabs, but not newlines
+func (self *tomlConfigParser) skipSpace() {
+	for self.pos < len(self.text) {
+		switch self.text[self.pos] {
+		case ' ', '\t', '\r':
+			self.pos++
+		default:
+			return
+		}
+	}
+}
+
+// Skip a comment, up to the newline
+func (self *tomlConfigParser) skipComment() {
+	for self.pos < len(self.text) && self.text[self.pos] != '\n' {
+		self.pos++
+	}
+}
+
+// Skip spaces, newlines, and comments, as are allowed inside an array
+func (self *tomlConfigParser) skipSpaceAndLines() {
+	for {
+		self.skipSpace()
+		if self.pos == len(self.text) {
+			return
+		}
+		switch self.text[self.pos] {
+		case '\n':
+			self.pos++
+			self.line++
+		case '#':
+			self.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// Only a comment can follow, up to the end of the line
+func (self *tomlConfigParser) endOfLine() error {
+	self.skipSpace()
+	if self.pos == len(self.text) {
+		return nil
+	}
+	switch self.text[self.pos] {
+	case '\n', '#':
+		return nil
+	default:
+		return self.syntaxError()
+	}
+}
+
+// Is the next character c? If so, it is consumed.
+func (self *tomlConfigParser) accept(c byte) bool {
+	if self.pos < len(self.text) && self.text[self.pos] == c {
+		self.pos++
+		return true
+	}
+	return false
+}
+
+// Parse a table header, like [sub] or [sub.db], which sets the section
+// of the keys that follow
+func (self *tomlConfigParser) parseTable() error {
+	self.pos++
+	if self.accept('[') {
+		// Arrays of tables are not supported
+		return self.syntaxError()
+	}
+	keys, err := self.parseKeys()
+	if err != nil {
+		return err
+	}
+	if !self.accept(']') {
+		return self.syntaxError()
+	}
+	self.section = keys
+	return self.endOfLine()
+}
+
+// Parse a key, which may be dotted, like db.max-conns, and may be quoted
+func (self *tomlConfigParser) parseKeys() ([]string, error) {
+	keys := []string{}
+	for {
+		self.skipSpace()
+		if self.pos == len(self.text) {
+			return nil, self.syntaxError()
+		}
+		var key string
+		var err error
+		switch self.text[self.pos] {
+		case '"', '\'':
+			key, err = self.parseString()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			start := self.pos
+			for self.pos < len(self.text) && isTOMLBareKeyChar(self.text[self.pos]) {
+				self.pos++
+			}
+			if self.pos == start {
+				return nil, self.syntaxError()
+			}
+			key = self.text[start:self.pos]
+		}
+		keys = append(keys, key)
+		self.skipSpace()
+		if !self.accept('.') {
+			return keys, nil
+		}
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// Parse a key = value line, or a key = value in an inline table
+func (self *tomlConfigParser) parseKeyValue(section []string) error {
+	line := self.line
+	keys, err := self.parseKeys()
+	if err != nil {
+		return err
+	}
+	if !self.accept('=') {
+		return self.syntaxError()
+	}
+	path := append(append([]string{}, section...), keys...)
+
+	self.skipSpace()
+	if self.accept('{') {
+		return self.parseInlineTable(path)
+	}
+
+	entry := &configEntry{
+		path: path,
+		line: line,
+	}
+	if self.accept('[') {
+		entry.list = true
+		entry.values, err = self.parseArray()
+	} else {
+		var value string
+		value, err = self.parseValue()
+		entry.values = []string{value}
+	}
+	if err != nil {
+		return err
+	}
+	self.entries = append(self.entries, entry)
+	return nil
+}
+
+// Parse the key = value pairs of an inline table, after its "{"
+func (self *tomlConfigParser) parseInlineTable(path []string) error {
+	self.skipSpace()
+	if self.accept('}') {
+		return nil
+	}
+	for {
+		err := self.parseKeyValue(path)
+		if err != nil {
+			return err
+		}
+		self.skipSpace()
+		if self.accept('}') {
+			return nil
+		}
+		if !self.accept(',') {
+			return self.syntaxError()
+		}
+	}
+}
+
+// Parse the items of an array, after its "[". The items cannot be arrays
+// or tables.
+func (self *tomlConfigParser) parseArray() ([]string, error) {
+	values := []string{}
+	for {
+		self.skipSpaceAndLines()
+		if self.accept(']') {
+			return values, nil
+		}
+		value, err := self.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		self.skipSpaceAndLines()
+		if self.accept(']') {
+			return values, nil
+		}
+		if !self.accept(',') {
+			return nil, self.syntaxError()
+		}
+	}
+}
+
+// Parse a string, or a bare value like a number, a boolean, or a date
+func (self *tomlConfigParser) parseValue() (string, error) {
+	if self.pos == len(self.text) {
+		return "", self.syntaxError()
+	}
+	switch self.text[self.pos] {
+	case '"', '\'':
+		return self.parseString()
+	case '[', '{':
+		return "", self.syntaxError()
+	}
+
+	start := self.pos
+	for self.pos < len(self.text) && !strings.ContainsRune(",]}#\n", rune(self.text[self.pos])) {
+		self.pos++
+	}
+	value := strings.TrimSpace(self.text[start:self.pos])
+	if value == "" {
+		return "", self.syntaxError()
+	}
+	// Numbers can have underscores between the digits, like 1_000
+	if strings.ContainsAny(value[:1], "0123456789+-") {
+		value = strings.Replace(value, "_", "", -1)
+	}
+	return value, nil
+}
+
+// Parse a basic string, which can have escapes, or a literal string,
+// which cannot. Neither can span lines.
+func (self *tomlConfigParser) parseString() (string, error) {
+	quote := self.text[self.pos]
+	if strings.HasPrefix(self.text[self.pos:], strings.Repeat(string(quote), 3)) {
+		// Multi-line strings are not supported
+		return "", self.syntaxError()
+	}
+	start := self.pos
+	self.pos++
+	for self.pos < len(self.text) {
+		c := self.text[self.pos]
+		switch {
+		case c == '\n':
+			return "", self.syntaxError()
+		case c == '\\' && quote == '"':
+			self.pos += 2
+			continue
+		case c == quote:
+			self.pos++
+			if quote == '\'' {
+				return self.text[start+1 : self.pos-1], nil
+			}
+			value, err := strconv.Unquote(self.text[start:self.pos])
+			if err != nil {
+				return "", self.syntaxError()
+			}
+			return value, nil
+		}
+		self.pos++
+	}
+	return "", self.syntaxError()
+}
@@ -0,0 +1,69 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Check the Requires, ConflictsWith, and RequiredIf fields of the arguments
+// in the triggered Command and its ancestors.
+func checkDependencies(cmdStack []*Command) error {
+	leafIndex := len(cmdStack) - 1
+
+	for i, cmd := range cmdStack {
+		args := make([]*Argument, 0, len(cmd.switchArguments)+len(cmd.positionalArguments))
+		args = append(args, cmd.switchArguments...)
+		args = append(args, cmd.positionalArguments...)
+
+		for _, arg := range args {
+			// Inherited arguments are checked in the triggered Command
+			if arg.Inherit && i < leafIndex {
+				continue
+			}
+			label := seenLabel(cmdStack, i, arg)
+
+			if label == "" {
+				for j, condition := range arg.RequiredIf {
+					other := arg.requiredIfArgs[j]
+					otherLabel := seenLabel(cmdStack, i, other)
+					if otherLabel == "" {
+						continue
+					}
+					if condition.Value == nil {
+						return fmt.Errorf("%s is required when %s is given",
+							arg.PrettyName(), otherLabel)
+					}
+					if valueMatches(other.value.getValue(), condition.Value) {
+						return fmt.Errorf("%s is required when %s is %v",
+							arg.PrettyName(), otherLabel, condition.Value)
+					}
+				}
+				continue
+			}
+
+			for _, other := range arg.requiresArgs {
+				if seenLabel(cmdStack, i, other) == "" {
+					return fmt.Errorf("%s requires %s", label, other.PrettyName())
+				}
+			}
+			for _, other := range arg.conflictsWithArgs {
+				otherLabel := seenLabel(cmdStack, i, other)
+				if otherLabel != "" {
+					return fmt.Errorf("%s cannot be used with %s", label, otherLabel)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Does the value in the destination field match the wanted value?
+func valueMatches(value reflect.Value, want interface{}) bool {
+	have := value.Interface()
+	if reflect.DeepEqual(have, want) {
+		return true
+	}
+	return fmt.Sprint(have) == fmt.Sprint(want)
+}
@@ -0,0 +1,234 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type DTestOptions struct {
+	TlsCert string
+	TlsKey  string
+	DryRun  bool
+	Force   bool
+	Format  string
+	Output  string
+	Level   int
+}
+
+func createDTestParser() (*DTestOptions, *ArgumentParser) {
+	opts := &DTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--tls-cert"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--tls-key"},
+		Requires: []string{"--tls-cert"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--force", "-f"},
+	})
+	ap.Add(&Argument{
+		Switches:      []string{"--dry-run", "-n"},
+		ConflictsWith: []string{"Force"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--format"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--output"},
+		RequiredIf: []Condition{
+			{Argument: "--format", Value: "file"},
+			{Argument: "--level", Value: 3},
+		},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestDependencyRequiresMet(c *C) {
+	opts, ap := createDTestParser()
+
+	argv := []string{"--tls-key", "k", "--tls-cert", "c"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.TlsKey, Equals, "k")
+	c.Check(opts.TlsCert, Equals, "c")
+}
+
+func (s *MySuite) TestDependencyRequiresNotMet(c *C) {
+	_, ap := createDTestParser()
+
+	argv := []string{"--tls-key", "k"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "--tls-key requires --tls-cert")
+}
+
+func (s *MySuite) TestDependencyConflicts(c *C) {
+	_, ap := createDTestParser()
+
+	argv := []string{"-f", "-n"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "-n cannot be used with -f")
+}
+
+func (s *MySuite) TestDependencyRequiredIfNotTriggered(c *C) {
+	opts, ap := createDTestParser()
+
+	argv := []string{"--format", "json", "--level", "2"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Format, Equals, "json")
+}
+
+func (s *MySuite) TestDependencyRequiredIfString(c *C) {
+	_, ap := createDTestParser()
+
+	argv := []string{"--format=file"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "--output is required when --format is file")
+}
+
+func (s *MySuite) TestDependencyRequiredIfInt(c *C) {
+	_, ap := createDTestParser()
+
+	argv := []string{"--level", "3"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "--output is required when --level is 3")
+}
+
+func (s *MySuite) TestDependencyRequiredIfMet(c *C) {
+	opts, ap := createDTestParser()
+
+	argv := []string{"--format=file", "--output", "out.txt"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Output, Equals, "out.txt")
+}
+
+func (s *MySuite) TestDependencyRequiredIfGiven(c *C) {
+	opts := &DTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--tls-cert"},
+	})
+	ap.Add(&Argument{
+		Switches:   []string{"--tls-key"},
+		RequiredIf: []Condition{{Argument: "--tls-cert"}},
+	})
+
+	results := ap.parseArgv([]string{"--tls-cert", "c"})
+	c.Check(results.parseError, ErrorMatches, "--tls-key is required when --tls-cert is given")
+}
+
+func (s *MySuite) TestDependencyUnknownPanics(c *C) {
+	_, ap := createDTestParser()
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--tls-ca"},
+			Dest:     "TlsCert",
+			Requires: []string{"--tls-unknown"},
+		})
+	}, PanicMatches, "Argument --tls-ca: Requires refers to --tls-unknown, which is not an argument .*")
+}
+
+func (s *MySuite) TestDependencyInheritedPanics(c *C) {
+	_, ap := createDTestParser()
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches:      []string{"--tls-ca"},
+			Dest:          "TlsCert",
+			ConflictsWith: []string{"--tls-key"},
+			Inherit:       true,
+		})
+	}, PanicMatches, "Argument --tls-ca: ConflictsWith refers to --tls-key, which is not inherited.*")
+}
+
+func (s *MySuite) TestDependencyInherited(c *C) {
+	opts := &DTestOptions{}
+	subOpts := &DTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--force"},
+		Inherit:  true,
+	})
+	ap.Add(&Argument{
+		Switches:      []string{"--dry-run"},
+		ConflictsWith: []string{"--force"},
+		Inherit:       true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"--force", "sub", "--dry-run"})
+	c.Check(results.parseError, ErrorMatches, "--dry-run cannot be used with --force")
+}
+
+func createNegatedDTestParser() (*DTestOptions, *ArgumentParser) {
+	opts := &DTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--force"},
+		Inherit:  true,
+	})
+	ap.Add(&Argument{
+		Switches:      []string{"--dry-run"},
+		ConflictsWith: []string{"--force"},
+		Negatable:     true,
+		Inherit:       true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--format"},
+		Requires: []string{"--dry-run"},
+	})
+	ap.Root.AddExclusiveGroup("--force", "--dry-run")
+	ap.New(&Command{
+		Name:   "sub",
+		Values: &DTestOptions{},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestDependencyNegated(c *C) {
+	// Turning a switch off does not count as giving it, for the
+	// dependencies or the groups
+	opts, ap := createNegatedDTestParser()
+	results := ap.parseArgv([]string{"--no-dry-run", "--force"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.DryRun, Equals, false)
+	c.Check(opts.Force, Equals, true)
+
+	_, ap = createNegatedDTestParser()
+	results = ap.parseArgv([]string{"--format", "x", "--no-dry-run"})
+	c.Check(results.parseError, ErrorMatches, "--format requires --dry-run")
+
+	_, ap = createNegatedDTestParser()
+	results = ap.parseArgv([]string{"--force", "sub", "--no-dry-run"})
+	c.Check(results.parseError, IsNil)
+
+	// The last switch wins
+	_, ap = createNegatedDTestParser()
+	results = ap.parseArgv([]string{"--force", "sub", "--no-dry-run", "--dry-run"})
+	c.Check(results.parseError, ErrorMatches, "--dry-run cannot be used with --force")
+}
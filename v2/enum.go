@@ -0,0 +1,135 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements enum destinations: named integer types whose
+// values are given by name.
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The registered mappings from names to values, by the type of the values
+var enumRegistry = map[reflect.Type]reflect.Value{}
+
+// Register the names of the values of a named integer type, so that
+// destination fields of that type are given by name. The mapping
+// must be a map[string]T, like:
+//
+//	argparse.RegisterEnum(map[string]Level{
+//		"debug": LevelDebug,
+//		"info":  LevelInfo,
+//	})
+//
+// Call it before adding the arguments, as from an init() function.
+// An Argument's EnumValues overrides the registered names.
+func RegisterEnum(mapping interface{}) {
+	mappingValue, err := checkEnumMapping(mapping)
+	if err != nil {
+		panic(fmt.Sprintf("RegisterEnum: %s", err))
+	}
+	enumRegistry[mappingValue.Type().Elem()] = mappingValue
+}
+
+// Check that the mapping is a map[string]T, where T is an integer type
+func checkEnumMapping(mapping interface{}) (reflect.Value, error) {
+	mappingValue := reflect.ValueOf(mapping)
+	if mappingValue.Kind() != reflect.Map || mappingValue.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("the enum mapping must be a map[string]T, not %T", mapping)
+	}
+	switch mappingValue.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return reflect.Value{}, fmt.Errorf("the enum mapping must be to an integer type, not %s",
+			mappingValue.Type().Elem().String())
+	}
+	if mappingValue.Len() == 0 {
+		return reflect.Value{}, fmt.Errorf("the enum mapping is empty")
+	}
+	return mappingValue, nil
+}
+
+// Check the EnumValues, and list the names of the values as the
+// choices for the help
+func (self *Argument) sanityCheckEnum() error {
+	itemType := self.itemType()
+	if self.EnumValues != nil {
+		mappingValue, err := checkEnumMapping(self.EnumValues)
+		if err != nil {
+			return fmt.Errorf("Argument %s: EnumValues: %w", self.PrettyName(), err)
+		}
+		if mappingValue.Type().Elem() != itemType {
+			return fmt.Errorf("Argument %s: EnumValues is a %s, but the destination is of type %s",
+				self.PrettyName(), mappingValue.Type().String(), itemType.String())
+		}
+	}
+
+	mapping, ok := self.enumMapping(itemType)
+	if !ok {
+		return nil
+	}
+	names := enumNames(mapping)
+	self.choiceHelp = make([]Choice, len(names))
+	for i, name := range names {
+		self.choiceHelp[i].Value = name
+	}
+	return nil
+}
+
+// The mapping for the item type, from the EnumValues, or the registered
+// mapping for the type
+func (self *Argument) enumMapping(itemType reflect.Type) (reflect.Value, bool) {
+	if self.EnumValues != nil {
+		mappingValue := reflect.ValueOf(self.EnumValues)
+		if mappingValue.Kind() == reflect.Map && mappingValue.Type().Elem() == itemType {
+			return mappingValue, true
+		}
+	}
+	mapping, ok := enumRegistry[itemType]
+	return mapping, ok
+}
+
+// Parse a name into its value. An exact match wins over a match that
+// ignores case.
+func (self *Argument) parseEnum(mapping reflect.Value, m *Messages, text string) (reflect.Value, error) {
+	value := enumValue(mapping, text)
+	if value.IsValid() {
+		return value, nil
+	}
+	if self.EnumIgnoreCase {
+		for _, name := range enumNames(mapping) {
+			if strings.EqualFold(name, text) {
+				return enumValue(mapping, name), nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf(m.ShouldBeAValidChoiceFmt, enumNames(mapping))
+}
+
+// The value for a name in the mapping, whose keys may be of a named
+// string type, or an invalid Value if the name is not in it
+func enumValue(mapping reflect.Value, name string) reflect.Value {
+	return mapping.MapIndex(reflect.ValueOf(name).Convert(mapping.Type().Key()))
+}
+
+// The names in the mapping, in the order of their values
+func enumNames(mapping reflect.Value) []string {
+	keys := mapping.MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iValue, _ := bigNumber(enumValue(mapping, names[i]))
+		jValue, _ := bigNumber(enumValue(mapping, names[j]))
+		if cmp := iValue.Cmp(jValue); cmp != 0 {
+			return cmp < 0
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,145 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type ETestLevel int
+
+const (
+	ETestLevelDebug ETestLevel = iota
+	ETestLevelInfo
+	ETestLevelWarn
+)
+
+type ETestShape uint8
+
+const (
+	ETestShapeCircle ETestShape = iota + 1
+	ETestShapeSquare
+)
+
+func init() {
+	RegisterEnum(map[string]ETestLevel{
+		"debug": ETestLevelDebug,
+		"info":  ETestLevelInfo,
+		"warn":  ETestLevelWarn,
+	})
+}
+
+type ETestOptions struct {
+	Level  ETestLevel
+	Levels []ETestLevel
+	Min    *ETestLevel
+	Shape  ETestShape
+}
+
+func createETestParser() (*ETestOptions, *ArgumentParser) {
+	opts := &ETestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+		Help:     "How much to log",
+	})
+	ap.Add(&Argument{
+		Switches:       []string{"--levels"},
+		EnumIgnoreCase: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--min"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--shape"},
+		EnumValues: map[string]ETestShape{
+			"circle": ETestShapeCircle,
+			"square": ETestShapeSquare,
+		},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestEnumValues(c *C) {
+	opts, ap := createETestParser()
+
+	argv := []string{"--level", "warn", "--levels", "INFO", "--levels", "Debug",
+		"--min", "info", "--shape", "square"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Level, Equals, ETestLevelWarn)
+	c.Check(opts.Levels, DeepEquals, []ETestLevel{ETestLevelInfo, ETestLevelDebug})
+	c.Assert(opts.Min, NotNil)
+	c.Check(*opts.Min, Equals, ETestLevelInfo)
+	c.Check(opts.Shape, Equals, ETestShapeSquare)
+}
+
+type ETestShapeName string
+
+// The names in EnumValues can be of a named string type
+func (s *MySuite) TestEnumNamedStringKeys(c *C) {
+	opts := &ETestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--shape"},
+		EnumValues: map[ETestShapeName]ETestShape{
+			"circle": ETestShapeCircle,
+			"square": ETestShapeSquare,
+		},
+		EnumIgnoreCase: true,
+	})
+
+	results := ap.parseArgv([]string{"--shape", "Square"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Shape, Equals, ETestShapeSquare)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--shape=SHAPE +\(one of: circle, square\)\n.*`)
+}
+
+func (s *MySuite) TestEnumBadName(c *C) {
+	_, ap := createETestParser()
+	results := ap.parseArgv([]string{"--level", "WARN"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --level: Not a valid choice. Should be one of: \[debug info warn\]`)
+
+	_, ap = createETestParser()
+	results = ap.parseArgv([]string{"--shape", "1"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --shape: Not a valid choice. Should be one of: \[circle square\]`)
+}
+
+func (s *MySuite) TestEnumHelp(c *C) {
+	_, ap := createETestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--level=LEVEL +How much to log \(one of: debug, info, warn\)\n.*`)
+	c.Check(help, Matches, `(?s).*--shape=SHAPE +\(one of: circle, square\)\n.*`)
+}
+
+func (s *MySuite) TestEnumBadDefinitions(c *C) {
+	_, ap := createETestParser()
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches:   []string{"--level2"},
+			Dest:       "Level",
+			EnumValues: map[string]ETestShape{"circle": ETestShapeCircle},
+		})
+	}, PanicMatches, "Argument --level2: EnumValues is a map\\[string\\]argparse.ETestShape, but the destination is of type argparse.ETestLevel")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches:   []string{"--level2"},
+			Dest:       "Level",
+			EnumValues: map[string]string{"debug": "0"},
+		})
+	}, PanicMatches, "Argument --level2: EnumValues: the enum mapping must be to an integer type, not string")
+	c.Check(func() {
+		RegisterEnum([]string{"debug"})
+	}, PanicMatches, `RegisterEnum: the enum mapping must be a map\[string\]T, not \[\]string`)
+}
@@ -0,0 +1,119 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements taking the values of switch arguments from
+// environment variables, when they are not given on the command-line.
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The name of the environment variable for an argument: its Env, or a
+// name made from the EnvPrefix of the Command (or its closest ancestor
+// that has one) and the Dest, like MYTOOL_LOG_LEVEL for LogLevel.
+// A name is not made for an argument that stores a constant or a count,
+// or that shares its Dest with another switch argument, as the variable
+// would not be for this argument alone; those need an Env.
+func (self *Command) envName(arg *Argument) string {
+	if arg.Env != "" || !arg.isSwitch() {
+		return arg.Env
+	}
+	switch arg.Action {
+	case ActionStoreConst, ActionAppendConst, ActionCount:
+		return ""
+	}
+	for _, other := range self.switchArguments {
+		if other != arg && other.Dest == arg.Dest {
+			return ""
+		}
+	}
+	for cmd := self; cmd != nil; cmd = cmd.parent {
+		if cmd.EnvPrefix != "" {
+			return cmd.EnvPrefix + envNameFromDest(arg.Dest)
+		}
+	}
+	return ""
+}
+
+// Convert a Dest, like DB.MaxConns, to the form of an environment
+// variable name, like DB_MAX_CONNS
+func envNameFromDest(dest string) string {
+	parts := strings.Split(dest, ".")
+	for i, part := range parts {
+		parts[i] = switchNameFromField(part)
+	}
+	name := strings.Join(parts, "_")
+	return strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// For each argument of the Commands that was not seen on the
+// command-line, take its value from its environment variable, if
+// that is set.
+func (self *ArgumentParser) applyEnv(cmdStack []*Command) error {
+	lookupEnv := self.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	for _, cmd := range cmdStack {
+		for _, arg := range cmd.switchArguments {
+			if arg.envName == "" {
+				continue
+			}
+			text, ok := lookupEnv(arg.envName)
+			if !ok {
+				continue
+			}
+			label := "$" + arg.envName
+			if cmd.seenLabels[arg] != "" || cmd.Seen[arg.Dest] {
+				cmd.addShadowedSource(arg, ValueSource{
+					Kind:  SourceEnv,
+					Label: label,
+				})
+				continue
+			}
+			given, err := arg.storeExternalValue(&self.Messages, label, text)
+			if err != nil {
+				return err
+			}
+			if given {
+				cmd.Seen[arg.Dest] = true
+				cmd.seenLabels[arg] = label
+				cmd.setSource(arg, ValueSource{
+					Kind:  SourceEnv,
+					Label: label,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// Store a value from an environment variable or a configuration file.
+// A switch that takes no value, like one with ActionCount, is only given
+// if the value is true. Returns whether the argument was given.
+func (self *Argument) storeExternalValue(m *Messages, label string, text string) (bool, error) {
+	if self.NumArgs > 0 || isBoolValueType(self.value) {
+		return true, self.storeValue(m, label, text)
+	}
+	given, err := strconv.ParseBool(text)
+	if err != nil {
+		return false, fmt.Errorf("While parsing value for %s: %w", label,
+			fmt.Errorf(m.CannotParseBooleanFmt, text))
+	}
+	if !given {
+		return false, nil
+	}
+	err = self.checkWithoutValue(m)
+	if err == nil {
+		err = self.value.seenWithoutValue(m)
+	}
+	if err != nil {
+		return false, fmt.Errorf("While parsing value for %s: %w", label, err)
+	}
+	return true, runCallback(self, label)
+}
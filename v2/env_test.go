@@ -0,0 +1,241 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type EnvTestOptions struct {
+	LogLevel string
+	Workers  int
+	Verbose  bool
+	Quiet    int
+	Token    string
+	DB       struct {
+		MaxConns int
+	}
+}
+
+func createEnvTestParser(env map[string]string) (*EnvTestOptions, *ArgumentParser) {
+	opts := &EnvTestOptions{}
+	ap := New(&Command{
+		Values:    opts,
+		EnvPrefix: "MYTOOL_",
+	})
+	ap.LookupEnv = func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	ap.Add(&Argument{
+		Switches: []string{"--log-level"},
+		Choices:  []string{"debug", "info"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--workers"},
+		Required: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v", "--verbose"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-q"},
+		Dest:     "Quiet",
+		Action:   ActionCount,
+		Env:      "MYTOOL_QUIET",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--token"},
+		Env:      "API_TOKEN",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.max-conns"},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestEnvFallback(c *C) {
+	opts, ap := createEnvTestParser(map[string]string{
+		"MYTOOL_LOG_LEVEL":    "debug",
+		"MYTOOL_WORKERS":      "4",
+		"MYTOOL_VERBOSE":      "true",
+		"MYTOOL_QUIET":        "1",
+		"API_TOKEN":           "secret",
+		"MYTOOL_DB_MAX_CONNS": "10",
+	})
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, "debug")
+	c.Check(opts.Workers, Equals, 4)
+	c.Check(opts.Verbose, Equals, true)
+	c.Check(opts.Quiet, Equals, 1)
+	c.Check(opts.Token, Equals, "secret")
+	c.Check(opts.DB.MaxConns, Equals, 10)
+	c.Check(ap.Root.Seen["LogLevel"], Equals, true)
+	c.Check(ap.Root.Seen["Quiet"], Equals, true)
+}
+
+func (s *MySuite) TestEnvCommandLineWins(c *C) {
+	opts, ap := createEnvTestParser(map[string]string{
+		"MYTOOL_LOG_LEVEL": "debug",
+		"MYTOOL_WORKERS":   "4",
+	})
+
+	results := ap.parseArgv([]string{"--log-level", "info", "--workers", "2"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, "info")
+	c.Check(opts.Workers, Equals, 2)
+}
+
+func (s *MySuite) TestEnvFalseSwitches(c *C) {
+	opts, ap := createEnvTestParser(map[string]string{
+		"MYTOOL_WORKERS": "4",
+		"MYTOOL_VERBOSE": "0",
+		"MYTOOL_QUIET":   "false",
+	})
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbose, Equals, false)
+	c.Check(opts.Quiet, Equals, 0)
+	c.Check(ap.Root.Seen["Verbose"], Equals, true)
+	c.Check(ap.Root.Seen["Quiet"], Equals, false)
+}
+
+// The environment is read again for each parse
+func (s *MySuite) TestEnvParseTwice(c *C) {
+	env := map[string]string{
+		"MYTOOL_WORKERS": "5",
+	}
+	opts, ap := createEnvTestParser(env)
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Workers, Equals, 5)
+
+	env["MYTOOL_WORKERS"] = "7"
+	results = ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Workers, Equals, 7)
+}
+
+func (s *MySuite) TestEnvErrors(c *C) {
+	_, ap := createEnvTestParser(map[string]string{
+		"MYTOOL_WORKERS":   "4",
+		"MYTOOL_LOG_LEVEL": "trace",
+	})
+	results := ap.parseArgv([]string{})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for \$MYTOOL_LOG_LEVEL: Not a valid choice. Should be one of: \[debug info\]`)
+
+	_, ap = createEnvTestParser(map[string]string{
+		"MYTOOL_WORKERS": "4",
+		"MYTOOL_QUIET":   "lots",
+	})
+	results = ap.parseArgv([]string{})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for \$MYTOOL_QUIET: Cannot convert "lots" to a boolean`)
+
+	_, ap = createEnvTestParser(map[string]string{})
+	results = ap.parseArgv([]string{})
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --workers")
+}
+
+func (s *MySuite) TestEnvInherited(c *C) {
+	opts := &EnvTestOptions{}
+	subOpts := &EnvTestOptions{}
+	ap := New(&Command{
+		Values:    opts,
+		EnvPrefix: "MYTOOL_",
+	})
+	ap.LookupEnv = func(name string) (string, bool) {
+		if name == "MYTOOL_LOG_LEVEL" {
+			return "info", true
+		}
+		return "", false
+	}
+	ap.Add(&Argument{
+		Switches: []string{"--log-level"},
+		Inherit:  true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"sub"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(subOpts.LogLevel, Equals, "info")
+}
+
+func (s *MySuite) TestEnvHelp(c *C) {
+	_, ap := createEnvTestParser(nil)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--workers=WORKERS +\[env: MYTOOL_WORKERS\] \(required\)\n.*`)
+	c.Check(help, Matches, `(?s).*--token=TOKEN +\[env: API_TOKEN\]\n.*`)
+	c.Check(help, Matches, `(?s).*--db.max-conns=DB.MAX-CONNS +\[env:\s+MYTOOL_DB_MAX_CONNS\]\n.*`)
+}
+
+type EnvSharedTestOptions struct {
+	LogLevel int
+	Verbose  int
+}
+
+// Arguments that store a constant or a count, or share a Dest, do not
+// get a variable from the EnvPrefix
+func (s *MySuite) TestEnvNoPrefixName(c *C) {
+	opts := &EnvSharedTestOptions{}
+	ap := New(&Command{
+		Values:    opts,
+		EnvPrefix: "T_",
+	})
+	ap.LookupEnv = func(name string) (string, bool) {
+		switch name {
+		case "T_LOG_LEVEL":
+			return "2", true
+		case "T_VERBOSE":
+			return "3", true
+		}
+		return "", false
+	}
+	ap.Add(&Argument{
+		Switches: []string{"--log-level"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--debug"},
+		Dest:     "LogLevel",
+		Action:   ActionStoreConst,
+		Const:    2,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--quiet"},
+		Dest:     "LogLevel",
+		Action:   ActionStoreConst,
+		Const:    0,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v"},
+		Dest:     "Verbose",
+		Action:   ActionCount,
+	})
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.LogLevel, Equals, 0)
+	c.Check(opts.Verbose, Equals, 0)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Not(Matches), `(?s).*\[env:.*`)
+}
+
+func (s *MySuite) TestEnvPositionalPanics(c *C) {
+	_, ap := createEnvTestParser(nil)
+	c.Check(func() {
+		ap.Add(&Argument{
+			Name: "token",
+			Env:  "TOKEN",
+		})
+	}, PanicMatches, "Argument token cannot have an Env because it is not a switch")
+}
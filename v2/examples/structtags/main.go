@@ -0,0 +1,45 @@
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gilramir/argparse/v2"
+)
+
+type MyOptions struct {
+	Debug    bool          `argparse:"--debug" help:"Set debug mode"`
+	Duration time.Duration `argparse:"--duration" help:"How long do you want to run?"`
+	Verbose  bool          `argparse:"-v,--verbose" help:"Set verbose mode"`
+	N        int           `argparse:"-n" help:"Number" choices:"1,2,5"`
+	Names    []string      `argparse:"names" nargs:"+" help:"Some names passed into the program"`
+}
+
+func main() {
+	opts := &MyOptions{}
+	ap := argparse.New(&argparse.Command{
+		Description: "This is an example program",
+		Values:      opts,
+	})
+
+	// Add the arguments described by the struct tags
+	ap.AddFromStruct()
+
+	// The library handles errors, and -h/--help
+	ap.Parse()
+
+	fmt.Printf("Verbose is %v\n", opts.Verbose)
+	fmt.Printf("Debug is %v\n", opts.Debug)
+	fmt.Printf("N is %v\n", opts.N)
+
+	if ap.Root.Seen["Duration"] {
+		fmt.Printf("Duration: %s\n", opts.Duration.String())
+	}
+
+	fmt.Printf("Number of names: %d\n", len(opts.Names))
+	for i := 0; i < len(opts.Names); i++ {
+		fmt.Printf("%d. %s\n", i+1, opts.Names[i])
+	}
+}
@@ -1,10 +1,16 @@
 module github.com/gilramir/argparse/v2
 
-go 1.14
+go 1.18
 
 require (
 	github.com/gilramir/consolesize v1.0.2
 	github.com/gilramir/unicodemonowidth v1.1.1
-	golang.org/x/sys v0.1.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15
 )
+
+require (
+	github.com/kr/pretty v0.2.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	golang.org/x/sys v0.1.0 // indirect
+	golang.org/x/text v0.3.2 // indirect
+)
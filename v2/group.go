@@ -0,0 +1,170 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The rule that an ArgumentGroup enforces on its Arguments
+type GroupConstraint int
+
+const (
+	// At most one of the Arguments can be given
+	MutuallyExclusive GroupConstraint = iota
+	// At least one of the Arguments must be given
+	AtLeastOne
+	// One, and only one, of the Arguments must be given
+	ExactlyOne
+)
+
+// A set of switch arguments in a Command that are checked together
+// after the parse, and that are shown together in the help output.
+type ArgumentGroup struct {
+	// The heading for the group in the help output. If not set, a
+	// heading describing the Constraint is used.
+	Title string
+
+	Constraint GroupConstraint
+
+	arguments []*Argument
+}
+
+// Add a group of switch arguments, which must already have been added
+// to this Command. Each member is given as a switch or a Dest.
+func (self *Command) AddGroup(constraint GroupConstraint, members ...string) *ArgumentGroup {
+	if len(members) < 2 {
+		panic(fmt.Sprintf("An argument group in Command %s needs at least 2 members",
+			self.Name))
+	}
+
+	group := &ArgumentGroup{
+		Constraint: constraint,
+	}
+	for _, member := range members {
+		arg := self.findArgument(member)
+		if arg == nil {
+			panic(fmt.Sprintf("Cannot add %s to an argument group in Command %s, "+
+				"because it is not an argument in that Command", member, self.Name))
+		}
+		if !arg.isSwitch() {
+			panic(fmt.Sprintf("Cannot add %s to an argument group in Command %s, "+
+				"because it is not a switch argument", member, self.Name))
+		}
+		group.arguments = append(group.arguments, arg)
+	}
+
+	self.groups = append(self.groups, group)
+	return group
+}
+
+// Add a group of switch arguments of which at most one can be given.
+func (self *Command) AddExclusiveGroup(members ...string) *ArgumentGroup {
+	return self.AddGroup(MutuallyExclusive, members...)
+}
+
+// Add a group of switch arguments of which at least one must be given.
+func (self *Command) AddRequiredOneOf(members ...string) *ArgumentGroup {
+	return self.AddGroup(AtLeastOne, members...)
+}
+
+// Add a group of switch arguments of which exactly one must be given.
+func (self *Command) AddExactlyOneOf(members ...string) *ArgumentGroup {
+	return self.AddGroup(ExactlyOne, members...)
+}
+
+// The heading for the group in the help output
+func (self *ArgumentGroup) title(m *Messages) string {
+	if self.Title != "" {
+		return self.Title
+	}
+	switch self.Constraint {
+	case MutuallyExclusive:
+		return m.MutuallyExclusiveTitle
+	case AtLeastOne:
+		return m.AtLeastOneTitle
+	case ExactlyOne:
+		return m.ExactlyOneTitle
+	default:
+		panic(fmt.Sprintf("Unexpected group constraint %d", self.Constraint))
+	}
+}
+
+// The first group that the argument belongs to; an argument is shown
+// in the help output with that group.
+func (self *Command) groupOf(arg *Argument) *ArgumentGroup {
+	for _, group := range self.groups {
+		if group.contains(arg) {
+			return group
+		}
+	}
+	return nil
+}
+
+func (self *ArgumentGroup) contains(arg *Argument) bool {
+	for _, member := range self.arguments {
+		if member == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the constraint, given the labels (as the user typed them) of the
+// arguments that were seen.
+func (self *ArgumentGroup) check(labels []string) error {
+	if len(labels) > 1 && (self.Constraint == MutuallyExclusive || self.Constraint == ExactlyOne) {
+		return fmt.Errorf("%s cannot be used together", strings.Join(labels, ", "))
+	}
+	if len(labels) == 0 && (self.Constraint == AtLeastOne || self.Constraint == ExactlyOne) {
+		names := make([]string, len(self.arguments))
+		for i, arg := range self.arguments {
+			names[i] = arg.Switches[0]
+		}
+		return fmt.Errorf("One of these switches is required: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Return the label that the user typed for an argument of the Command
+// at cmdStack[i], or "" if it was not seen. An inherited argument may be
+// given before or after a sub-command, and its value is propagated to the
+// triggered Command, so that is where it is checked. A switch turned off
+// with its "--no-" switch counts as not given.
+func seenLabel(cmdStack []*Command, i int, arg *Argument) string {
+	cmd := cmdStack[i]
+	leaf := cmdStack[len(cmdStack)-1]
+	if arg.Inherit && i < len(cmdStack)-1 {
+		for _, leafArg := range leaf.switchArguments {
+			if leafArg.Inherit && leafArg.Dest == arg.Dest {
+				cmd, arg = leaf, leafArg
+				break
+			}
+		}
+	}
+	if cmd.negated[arg] {
+		return ""
+	}
+	return cmd.seenLabels[arg]
+}
+
+// Check the argument groups of the triggered Command and its ancestors
+func checkGroups(cmdStack []*Command) error {
+	for i, cmd := range cmdStack {
+		for _, group := range cmd.groups {
+			var labels []string
+			for _, arg := range group.arguments {
+				label := seenLabel(cmdStack, i, arg)
+				if label != "" {
+					labels = append(labels, label)
+				}
+			}
+			err := group.check(labels)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type GTestOptions struct {
+	Json  bool
+	Yaml  bool
+	Table bool
+	File  string
+	Url   string
+	Name  string
+}
+
+type GTestSubOptions struct {
+	GTestOptions
+}
+
+func createGTestParser() (*GTestOptions, *ArgumentParser) {
+	opts := &GTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--json", "-j"},
+		Help:     "Output JSON",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--yaml", "-y"},
+		Help:     "Output YAML",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--table", "-t"},
+		Help:     "Output a table",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--file"},
+		Help:     "Read from a file",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--url"},
+		Help:     "Read from a URL",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--name"},
+		Help:     "A name",
+	})
+	ap.Root.AddExclusiveGroup("--json", "--yaml", "Table")
+	ap.Root.AddExactlyOneOf("--file", "--url")
+	return opts, ap
+}
+
+func (s *MySuite) TestGroupExclusiveOne(c *C) {
+	opts, ap := createGTestParser()
+
+	argv := []string{"--yaml", "--file", "x"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Yaml, Equals, true)
+}
+
+func (s *MySuite) TestGroupExclusiveConflict(c *C) {
+	_, ap := createGTestParser()
+
+	argv := []string{"-y", "--file", "x", "--table"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "-y, --table cannot be used together")
+}
+
+func (s *MySuite) TestGroupExactlyOneMissing(c *C) {
+	_, ap := createGTestParser()
+
+	argv := []string{"--json"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		"One of these switches is required: --file, --url")
+}
+
+func (s *MySuite) TestGroupExactlyOneTooMany(c *C) {
+	_, ap := createGTestParser()
+
+	argv := []string{"--url", "x", "--file", "y"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "--file, --url cannot be used together")
+}
+
+// Each parse checks only the switches given to it
+func (s *MySuite) TestGroupParseTwice(c *C) {
+	_, ap := createGTestParser()
+
+	results := ap.parseArgv([]string{"--json", "--file", "x"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(ap.Root.Seen["Json"], Equals, true)
+
+	results = ap.parseArgv([]string{"--yaml", "--file", "x"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(ap.Root.Seen["Json"], Equals, false)
+	c.Check(ap.Root.Seen["Yaml"], Equals, true)
+
+	results = ap.parseArgv([]string{})
+	c.Check(results.parseError, ErrorMatches,
+		"One of these switches is required: --file, --url")
+}
+
+func (s *MySuite) TestGroupAtLeastOne(c *C) {
+	_, ap := createGTestParser()
+	ap.Root.AddRequiredOneOf("--name", "--json")
+
+	results := ap.parseArgv([]string{"--file", "x"})
+	c.Check(results.parseError, ErrorMatches,
+		"One of these switches is required: --name, --json")
+
+	_, ap = createGTestParser()
+	ap.Root.AddRequiredOneOf("--name", "--json")
+
+	results = ap.parseArgv([]string{"--file", "x", "--name", "a", "--json"})
+	c.Check(results.parseError, IsNil)
+}
+
+func (s *MySuite) TestGroupUnknownMemberPanics(c *C) {
+	_, ap := createGTestParser()
+	c.Check(func() { ap.Root.AddExclusiveGroup("--json", "--xml") },
+		PanicMatches, "Cannot add --xml to an argument group .*")
+	c.Check(func() { ap.Root.AddExclusiveGroup("--json") },
+		PanicMatches, "An argument group .* needs at least 2 members")
+}
+
+func (s *MySuite) TestGroupInheritedMembers(c *C) {
+	opts := &GTestOptions{}
+	subOpts := &GTestSubOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--json"},
+		Inherit:  true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--yaml"},
+		Inherit:  true,
+	})
+	ap.Root.AddExclusiveGroup("--json", "--yaml")
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	// One before the sub-command, and one after
+	results := ap.parseArgv([]string{"--json", "sub", "--yaml"})
+	c.Check(results.parseError, ErrorMatches, "--json, --yaml cannot be used together")
+}
+
+func (s *MySuite) TestGroupHelp(c *C) {
+	_, ap := createGTestParser()
+	ap.Root.groups[0].Title = "Output formats"
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--name=NAME +A name.*`+
+		`Output formats:\n\n +--json,-j +Output JSON\n +--yaml,-y +Output YAML\n +--table,-t +Output a table\n.*`+
+		`Exactly one of these options is required:\n\n +--file=FILE +Read from a file\n +--url=URL +Read from a URL\n.*`)
+}
@@ -3,6 +3,7 @@ package argparse
 // Copyright (c) 2020 by Gilbert Ramirez <gram@alumni.rice.edu>
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -10,8 +11,6 @@ import (
 	"github.com/gilramir/unicodemonowidth"
 )
 
-// TODO - the help should show Choices, if available
-
 // This should honor width too
 func (self *ArgumentParser) usageString(cmd *Command, width int, ancestorCommands []*Command) string {
 	var usage string
@@ -49,6 +48,7 @@ func (self *ArgumentParser) usageString(cmd *Command, width int, ancestorCommand
 func (self *ArgumentParser) helpString(cmd *Command,
 	ancestorCommands []*Command) string {
 	var text string
+	self.Messages.fillDefaults()
 
 	width := 80
 	wh, err := consolesize.GetConsoleWidthHeight()
@@ -62,22 +62,12 @@ func (self *ArgumentParser) helpString(cmd *Command,
 	// Switch arguments
 
 	for _, arg := range cmd.switchArguments {
-		argumentStrings := arg.Switches
-		if !arg.isPositional() && arg.NumArgs > 0 {
-			// set a default metavar?
-			var metavar string
-			if arg.MetaVar == "" {
-				// Use the upper-case version of the first switch, with
-				// no dashes at the front.
-				metavar = strings.TrimLeft(strings.ToUpper(arg.Switches[0]), "-")
-			} else {
-				metavar = arg.MetaVar
-			}
-			// Add the metavar to the last one
-			idx := len(argumentStrings) - 1
-			argumentStrings[idx] = argumentStrings[idx] + "=" + metavar
+		if cmd.groupOf(arg) != nil {
+			// Shown with its group
+			continue
 		}
-		formatter.addOption(argumentStrings, arg.Help)
+		lhs, help := self.switchHelpRow(arg)
+		self.addArgumentOption(formatter, arg, lhs, help)
 	}
 	formatter.addOption(self.HelpSwitches, self.Messages.HelpDescription)
 
@@ -98,11 +88,26 @@ func (self *ArgumentParser) helpString(cmd *Command,
 		} else if arg.NumArgsGlob == "*" {
 			argName = "[" + argName + "[ ... ] ]"
 		}
-		formatter.addOption([]string{argName}, arg.Help)
+		self.addArgumentOption(formatter, arg, []string{argName}, self.addValueHelp(arg, arg.Help))
 	}
 
 	text += formatter.produceString(width)
 
+	// Argument groups
+	for _, group := range cmd.groups {
+		groupFormatter := &helpFormatter{}
+		for _, arg := range group.arguments {
+			if cmd.groupOf(arg) == group {
+				lhs, help := self.switchHelpRow(arg)
+				self.addArgumentOption(groupFormatter, arg, lhs, help)
+			}
+		}
+		if len(groupFormatter.rows) > 0 {
+			text += "\n" + group.title(&self.Messages) + ":\n\n"
+			text += groupFormatter.produceString(width)
+		}
+	}
+
 	// Sub-commands
 	if len(cmd.subCommands) > 0 {
 		text += "\n" + self.Messages.SubCommands + ":\n\n"
@@ -137,3 +142,111 @@ func (self *ArgumentParser) helpString(cmd *Command,
 
 	return text
 }
+
+// The switches (with the metavar) and the help text for a switch argument
+func (self *ArgumentParser) switchHelpRow(arg *Argument) ([]string, string) {
+	argumentStrings := make([]string, len(arg.Switches))
+	copy(argumentStrings, arg.Switches)
+	if arg.Negatable {
+		for i, switchName := range argumentStrings {
+			if strings.HasPrefix(switchName, "--") {
+				argumentStrings[i] = "--[no-]" + switchName[2:]
+			}
+		}
+	}
+	if arg.NumArgs > 0 {
+		// set a default metavar?
+		var metavar string
+		mapValue, isMap := arg.value.(*mapValueT)
+		if arg.MetaVar == "" && isMap {
+			metavar = mapValue.metaVar()
+		} else if arg.MetaVar == "" {
+			// Use the upper-case version of the first switch, with
+			// no dashes at the front.
+			metavar = strings.TrimLeft(strings.ToUpper(arg.Switches[0]), "-")
+		} else {
+			metavar = arg.MetaVar
+		}
+		// Add the metavar to the last one. A map item has its own
+		// "=", so it is separated from the switch by a space.
+		idx := len(argumentStrings) - 1
+		if isMap {
+			argumentStrings[idx] = argumentStrings[idx] + " " + metavar
+		} else {
+			argumentStrings[idx] = argumentStrings[idx] + "=" + metavar
+		}
+	}
+	help := self.addValueHelp(arg, arg.Help)
+	if arg.envName != "" {
+		help = strings.TrimSpace(help + " " + fmt.Sprintf(self.Messages.EnvHelpFmt, arg.envName))
+	}
+	if arg.Required {
+		help = strings.TrimSpace(help + " " + self.Messages.RequiredHelp)
+	}
+	return argumentStrings, help
+}
+
+// Add a hint about the syntax of the value to the help text, for
+// types with units, and the constraints on the value
+func (self *ArgumentParser) addValueHelp(arg *Argument, help string) string {
+	value := arg.value
+	if pointer, ok := value.(*pointerValueT); ok {
+		value = pointer.elem
+	}
+	var hint string
+	switch v := value.(type) {
+	case *durationValueT, *durationSliceValueT:
+		hint = self.Messages.DurationHelp
+	case *funcValueT:
+		if v.itemType == byteSizeType {
+			hint = self.Messages.ByteSizeHelp
+		}
+	}
+	help = strings.TrimSpace(help + " " + hint)
+	help = strings.TrimSpace(help + " " + arg.constraintHelp(&self.Messages))
+
+	// Choices without descriptions are listed in the help text
+	choices := self.helpChoices(arg)
+	if len(choices) > 0 && !hasChoiceHelp(choices) {
+		values := make([]string, len(choices))
+		for i, choice := range choices {
+			values[i] = fmt.Sprint(choice.Value)
+		}
+		help = strings.TrimSpace(help + " " +
+			fmt.Sprintf(self.Messages.ChoicesHelpFmt, strings.Join(values, ", ")))
+	}
+	return help
+}
+
+// Add the row for an argument, followed by a row for each of its
+// Choices, if they have descriptions
+func (self *ArgumentParser) addArgumentOption(formatter *helpFormatter, arg *Argument,
+	lhs []string, help string) {
+	formatter.addOption(lhs, help)
+
+	choices := self.helpChoices(arg)
+	if !hasChoiceHelp(choices) {
+		return
+	}
+	for _, choice := range choices {
+		formatter.addOption([]string{"  " + fmt.Sprint(choice.Value)}, choice.Help)
+	}
+}
+
+// The Choices of an argument, calling its ChoicesFunc if it has one.
+// If the ChoicesFunc fails, the help has no choices to show. The choices
+// are not kept, so the next parse calls the ChoicesFunc again.
+func (self *ArgumentParser) helpChoices(arg *Argument) []Choice {
+	if arg.ChoicesFunc == nil || arg.choicesLoaded {
+		return arg.choiceHelp
+	}
+	choices, err := arg.ChoicesFunc()
+	if err != nil {
+		return nil
+	}
+	_, descriptions, err := normalizeChoices(choices)
+	if err != nil {
+		return nil
+	}
+	return descriptions
+}
@@ -2,6 +2,10 @@ package argparse
 
 // Copyright (c) 2020 by Gilbert Ramirez <gram@alumni.rice.edu>
 
+import (
+	"reflect"
+)
+
 // Strings that can be printed out to the user. They can be
 // overridden for i18n
 type Messages struct {
@@ -15,6 +19,44 @@ type Messages struct {
 	// "See this list of options"
 	HelpDescription string
 
+	// Added to the help text of a Required switch argument:
+	// "(required)"
+	RequiredHelp string
+
+	// Added to the help text of arguments whose values have units:
+	// "(a size, like 512k, 1.5G, or 10MiB)"
+	ByteSizeHelp string
+	// "(a duration, like 90s, 1h30m, or 7d)"
+	DurationHelp string
+
+	// Added to the help text of arguments with Min, Max, or Pattern:
+	// "(from %v to %v)"
+	BetweenHelpFmt string
+	// "(at least %v)"
+	MinHelpFmt string
+	// "(at most %v)"
+	MaxHelpFmt string
+	// "(matching %s)"
+	PatternHelpFmt string
+
+	// Added to the help text of arguments with Choices that have no
+	// descriptions:
+	// "(one of: %s)"
+	ChoicesHelpFmt string
+
+	// Added to the help text of arguments that can be given in an
+	// environment variable:
+	// "[env: %s]"
+	EnvHelpFmt string
+
+	// The headings for argument groups in the help output:
+	// "Mutually exclusive options"
+	MutuallyExclusiveTitle string
+	// "At least one of these options is required"
+	AtLeastOneTitle string
+	// "Exactly one of these options is required"
+	ExactlyOneTitle string
+
 	// Error when parsing a boolean
 	// "Cannot convert \"%s\" to a boolean"
 	CannotParseBooleanFmt string
@@ -27,14 +69,120 @@ type Messages struct {
 	// "Not a valid choice. Should be one of: %v"
 	// TODO This should be changed to have %s and %v, to show the incorrect value
 	ShouldBeAValidChoiceFmt string
+
+	// The ChoicesFunc failed, or returned bad choices
+	// "Cannot get the choices: %w"
+	CannotGetChoicesFmt string
+
+	// A map item is missing the separator between the key and value
+	// "Expected KEY%sVALUE but got \"%s\""
+	MissingKeyValueSeparatorFmt string
+
+	// A key was given twice for a map that does not allow it
+	// "The key \"%s\" was already given"
+	DuplicateKeyFmt string
+
+	// The value is outside of Min or Max
+	// "%v is less than the minimum of %v"
+	LessThanMinFmt string
+	// "%v is more than the maximum of %v"
+	MoreThanMaxFmt string
+
+	// The value does not match the Pattern
+	// "\"%s\" does not match the pattern %s"
+	DoesNotMatchPatternFmt string
+
+	// The configuration file cannot be read
+	// "Cannot read the configuration file: %w"
+	CannotReadConfigFmt string
+
+	// A line of the configuration file cannot be parsed
+	// "Cannot parse: %s"
+	ConfigSyntaxErrorFmt string
+
+	// A key in the configuration file is not an argument
+	// "Unknown key \"%s\""
+	UnknownConfigKeyFmt string
+
+	// A key in the configuration file is given more than once
+	// "The key \"%s\" is given more than once"
+	DuplicateConfigKeyFmt string
+
+	// A list is given in the configuration file for a single value
+	// "%s takes a single value, not a list"
+	ConfigNotAListFmt string
+
+	// A quote is not closed in a response file or string of arguments
+	// "The %s quote is not closed"
+	UnterminatedQuoteFmt string
+
+	// Where an error is in the string given to ParseString
+	// "At column %d: %w"
+	AtColumnFmt string
+	// "At line %d, column %d: %w"
+	AtLineColumnFmt string
+
+	// A response file cannot be read
+	// "Cannot read the response file: %w"
+	CannotReadResponseFileFmt string
+
+	// A response file names itself, or a file that names it
+	// "The response file %s includes itself"
+	ResponseFileCycleFmt string
 }
 
 var DefaultMessages_en = Messages{
 	SubCommands:     "Sub-Commands",
 	Options:         "Options",
 	HelpDescription: "See this list of options",
+	RequiredHelp:    "(required)",
+	ByteSizeHelp:    "(a size, like 512k, 1.5G, or 10MiB)",
+	DurationHelp:    "(a duration, like 90s, 1h30m, or 7d)",
+	BetweenHelpFmt:  "(from %v to %v)",
+	MinHelpFmt:      "(at least %v)",
+	MaxHelpFmt:      "(at most %v)",
+	PatternHelpFmt:  "(matching %s)",
+	ChoicesHelpFmt:  "(one of: %s)",
+	EnvHelpFmt:      "[env: %s]",
+
+	MutuallyExclusiveTitle: "Mutually exclusive options",
+	AtLeastOneTitle:        "At least one of these options is required",
+	ExactlyOneTitle:        "Exactly one of these options is required",
 
 	CannotParseBooleanFmt:   "Cannot convert \"%s\" to a boolean",
 	ChoicesOfWrongTypeFmt:   "Choices should be []%s",
 	ShouldBeAValidChoiceFmt: "Not a valid choice. Should be one of: %v",
+	CannotGetChoicesFmt:     "Cannot get the choices: %w",
+
+	MissingKeyValueSeparatorFmt: "Expected KEY%sVALUE but got \"%s\"",
+	DuplicateKeyFmt:             "The key \"%s\" was already given",
+
+	LessThanMinFmt:         "%v is less than the minimum of %v",
+	MoreThanMaxFmt:         "%v is more than the maximum of %v",
+	DoesNotMatchPatternFmt: "\"%s\" does not match the pattern %s",
+
+	CannotReadConfigFmt:   "Cannot read the configuration file: %w",
+	ConfigSyntaxErrorFmt:  "Cannot parse: %s",
+	UnknownConfigKeyFmt:   "Unknown key \"%s\"",
+	DuplicateConfigKeyFmt: "The key \"%s\" is given more than once",
+	ConfigNotAListFmt:     "%s takes a single value, not a list",
+
+	UnterminatedQuoteFmt:      "The %s quote is not closed",
+	AtColumnFmt:               "At column %d: %w",
+	AtLineColumnFmt:           "At line %d, column %d: %w",
+	CannotReadResponseFileFmt: "Cannot read the response file: %w",
+	ResponseFileCycleFmt:      "The response file %s includes itself",
+}
+
+// Use the DefaultMessages_en text for each message that is empty, so that
+// Messages translated before a message was added still work.
+func (self *Messages) fillDefaults() {
+	messages := reflect.ValueOf(self).Elem()
+	defaults := reflect.ValueOf(DefaultMessages_en)
+	for i := 0; i < messages.NumField(); i++ {
+		field := messages.Field(i)
+		if field.Kind() == reflect.String && field.String() == "" {
+			field.Set(defaults.Field(i))
+		}
+	}
 }
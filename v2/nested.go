@@ -0,0 +1,44 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements destinations in nested structs of the Values
+// struct, given by dotted paths like "DB.Host".
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Find the field for a dotted path through nested structs, and return
+// it with the path of field names, which is the Dest. If fromSwitch is
+// true, each part of the path is converted as argumentVariableName()
+// does, and matches the field name in any case, so "db.host" finds
+// DB.Host.
+func findDestField(structValue reflect.Value, path string, fromSwitch bool) (reflect.Value, string, bool) {
+	var fieldNames []string
+	value := structValue
+
+	for _, part := range strings.Split(path, ".") {
+		if value.Kind() != reflect.Struct || part == "" {
+			return reflect.Value{}, "", false
+		}
+		structType := value.Type()
+		field, found := structType.FieldByName(part)
+		if !found && fromSwitch {
+			name := argumentVariableName(part)
+			field, found = structType.FieldByName(name)
+			if !found {
+				field, found = structType.FieldByNameFunc(func(fieldName string) bool {
+					return strings.EqualFold(fieldName, name)
+				})
+			}
+		}
+		if !found {
+			return reflect.Value{}, "", false
+		}
+		value = value.FieldByIndex(field.Index)
+		fieldNames = append(fieldNames, field.Name)
+	}
+	return value, strings.Join(fieldNames, "."), true
+}
@@ -0,0 +1,131 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type NestedTestDB struct {
+	Host string `argparse:"--host" help:"The database host"`
+	Port int    `argparse:"-p,--port"`
+}
+
+type NestedTestCache struct {
+	Servers []string `argparse:"" help:"The cache servers"`
+	TTL     int
+}
+
+type NestedTestOptions struct {
+	Verbose bool            `argparse:"-v,--verbose"`
+	DB      NestedTestDB    `prefix:"db."`
+	Cache   NestedTestCache `prefix:"cache-"`
+}
+
+func (s *MySuite) TestNestedDottedSwitches(c *C) {
+	opts := &NestedTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.host"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.port"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--ttl"},
+		Dest:     "Cache.TTL",
+	})
+
+	argv := []string{"--db.host", "pg.example", "--db.port", "5432", "--ttl", "60"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.DB.Host, Equals, "pg.example")
+	c.Check(opts.DB.Port, Equals, 5432)
+	c.Check(opts.Cache.TTL, Equals, 60)
+	c.Check(ap.Root.Seen["DB.Host"], Equals, true)
+	c.Check(ap.Root.Seen["DB.Port"], Equals, true)
+	c.Check(ap.Root.Seen["Cache.TTL"], Equals, true)
+}
+
+func (s *MySuite) TestNestedTopLevelFieldFirst(c *C) {
+	type Options struct {
+		DbHost string
+		DB     NestedTestDB
+	}
+	opts := &Options{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.host"},
+	})
+
+	results := ap.parseArgv([]string{"--db.host", "x"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.DbHost, Equals, "x")
+	c.Check(opts.DB.Host, Equals, "")
+}
+
+func (s *MySuite) TestNestedBadDest(c *C) {
+	ap := New(&Command{
+		Values: &NestedTestOptions{},
+	})
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--db.user"},
+		})
+	}, PanicMatches, "Could not find destination field for argument --db.user; checked DbUser,db.user")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--host"},
+			Dest:     "DB.Host.Name",
+		})
+	}, PanicMatches, "Could not find destination field for argument --host, given as DB.Host.Name")
+}
+
+func (s *MySuite) TestNestedTags(c *C) {
+	opts := &NestedTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.AddFromStruct()
+
+	argv := []string{"-v", "--db.host", "pg.example", "-p", "5432",
+		"--cache-servers", "a", "--cache-servers", "b"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbose, Equals, true)
+	c.Check(opts.DB.Host, Equals, "pg.example")
+	c.Check(opts.DB.Port, Equals, 5432)
+	c.Check(opts.Cache.Servers, DeepEquals, []string{"a", "b"})
+	c.Check(ap.Root.Seen["Cache.Servers"], Equals, true)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--db.host=DB.HOST +The database host\n.*`)
+	c.Check(help, Matches, `(?s).*-p,--db.port=P +\n.*`)
+	c.Check(help, Matches, `(?s).*--cache-servers=CACHE-SERVERS +The cache servers\n.*`)
+}
+
+func (s *MySuite) TestNestedInherited(c *C) {
+	opts := &NestedTestOptions{}
+	subOpts := &NestedTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--db.host"},
+		Inherit:  true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"--db.host", "pg.example", "sub"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(subOpts.DB.Host, Equals, "pg.example")
+}
@@ -5,6 +5,7 @@ package argparse
 import (
 	"errors"
 	"fmt"
+	"reflect"
 
 	//	"log"
 	"strings"
@@ -28,6 +29,7 @@ const (
 	tokValueNotPresent
 	tokSubParser
 	tokHelp
+	tokNegatedArgument
 )
 
 type argToken struct {
@@ -49,10 +51,12 @@ type parserState struct {
 	ap         *ArgumentParser
 	pos        int
 	args       []string
-	tokenChan  chan argToken
 	tokens     []argToken
 	lastSwitch string
 
+	// Where each of the args came from, if response files are allowed
+	origins []argOrigin
+
 	cmd *Command
 	// If there are sub commands that could be present,
 	// this starts as true. Once an arg is parsed, no
@@ -71,33 +75,37 @@ type parserState struct {
 // Each parser state is a function
 type stateFunc func() stateFunc
 
+func (self *parserState) emit(token argToken) {
+	self.tokens = append(self.tokens, token)
+}
+
 func (self *parserState) emitWithArgument(typ tokenType, argument *Argument, label string) {
-	self.tokenChan <- argToken{
+	self.emit(argToken{
 		typ:           typ,
 		pos:           self.pos,
 		argument:      argument,
 		argumentLabel: label,
-	}
+	})
 }
 func (self *parserState) emitWithValue(typ tokenType, value string) {
-	self.tokenChan <- argToken{
+	self.emit(argToken{
 		typ:   typ,
 		pos:   self.pos,
 		value: value,
-	}
+	})
 }
 func (self *parserState) emitParser(cmd *Command) {
-	self.tokenChan <- argToken{
+	self.emit(argToken{
 		typ:     tokSubParser,
 		pos:     self.pos,
 		command: cmd,
-	}
+	})
 }
 func (self *parserState) emitToken(typ tokenType) {
-	self.tokenChan <- argToken{
+	self.emit(argToken{
 		typ: typ,
 		pos: self.pos,
-	}
+	})
 }
 
 // The entrance to the parser
@@ -110,31 +118,67 @@ func (self *parserState) runParser(ap *ArgumentParser, argv []string) *parseResu
 	// Initialize our state
 	self.ap = ap
 	self.args = argv
-	self.tokenChan = make(chan argToken)
+	ap.Root.resetParse()
 
 	self.subCommandAllowed = len(ap.Root.subCommands) > 0
 	self.cmd = ap.Root
 
-	// The parsing happens in a goroutine
-	go self._parse()
+	// Run the state machine to completion, collecting the tokens.
+	// Every state that emits a tokError or tokHelp is a final state,
+	// so the tokens can then be evaluated in order.
+	self._parse()
 
 	var lastArgLabel string
 	var lastArgument *Argument
 
-	for argToken := range self.tokenChan {
+	for _, argToken := range self.tokens {
 		switch argToken.typ {
 		case tokArgument:
-			self.cmd.Seen[argToken.argument.Dest] = true
+			results.triggeredCommand.Seen[argToken.argument.Dest] = true
+			results.triggeredCommand.seenLabels[argToken.argument] = argToken.argumentLabel
+			results.triggeredCommand.setSource(argToken.argument,
+				self.commandLineSource(argToken))
+			delete(results.triggeredCommand.negated, argToken.argument)
 			lastArgument = argToken.argument
 			lastArgLabel = argToken.argumentLabel
+			// The values given with this switch replace the slice
+			if lastArgument.Action == ActionReplace {
+				slice := lastArgument.value.getValue()
+				slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+			}
 			// If the argument is a boolean argument (no value), then
 			// we mark it as seen and move on.
 			if lastArgument.NumArgs == 0 {
-				err := lastArgument.value.seenWithoutValue()
+				err := lastArgument.checkWithoutValue(&ap.Messages)
+				if err != nil {
+					results.parseError = self.errorAt(argToken.pos, fmt.Errorf(
+						"While parsing value for %s: %w", lastArgLabel, err))
+					return results
+				}
+				err = lastArgument.value.seenWithoutValue(&ap.Messages)
 				if err != nil {
 					panic(fmt.Sprintf("not reached for arg %s: %s",
 						lastArgLabel, err))
 				}
+				err = runCallback(lastArgument, lastArgLabel)
+				if err != nil {
+					results.parseError = self.errorAt(argToken.pos, err)
+					return results
+				}
+			}
+
+		case tokNegatedArgument:
+			results.triggeredCommand.Seen[argToken.argument.Dest] = true
+			results.triggeredCommand.seenLabels[argToken.argument] = argToken.argumentLabel
+			results.triggeredCommand.setSource(argToken.argument,
+				self.commandLineSource(argToken))
+			results.triggeredCommand.negated[argToken.argument] = true
+			lastArgument = argToken.argument
+			lastArgLabel = argToken.argumentLabel
+			err := lastArgument.storeValue(&ap.Messages, lastArgLabel, "false")
+			if err != nil {
+				results.parseError = self.errorAt(argToken.pos, err)
+				return results
 			}
 
 		case tokValue:
@@ -144,10 +188,9 @@ func (self *parserState) runParser(ap *ArgumentParser, argv []string) *parseResu
 
 			// Parse the text and validate against the Choices, if there
 			// are any set for this Argument
-			err := lastArgument.value.parse(&ap.Messages, argToken.value)
+			err := lastArgument.storeValue(&ap.Messages, lastArgLabel, argToken.value)
 			if err != nil {
-				results.parseError = fmt.Errorf(
-					"While parsing value for %s: %w", lastArgLabel, err)
+				results.parseError = self.errorAt(argToken.pos, err)
 				return results
 			}
 		case tokValueNotPresent:
@@ -155,10 +198,13 @@ func (self *parserState) runParser(ap *ArgumentParser, argv []string) *parseResu
 				panic("Found ValueNotPresent without a preceding argument")
 			}
 			// only bools can have no value
-			err := lastArgument.value.seenWithoutValue()
+			err := lastArgument.checkWithoutValue(&ap.Messages)
+			if err == nil {
+				err = lastArgument.value.seenWithoutValue(&ap.Messages)
+			}
 			if err != nil {
-				results.parseError = fmt.Errorf(
-					"%s argument: %w", lastArgLabel, err)
+				results.parseError = self.errorAt(argToken.pos, fmt.Errorf(
+					"%s argument: %w", lastArgLabel, err))
 				return results
 			}
 		case tokSubParser:
@@ -169,22 +215,13 @@ func (self *parserState) runParser(ap *ArgumentParser, argv []string) *parseResu
 			results.helpRequested = true
 			return results
 		case tokError:
-			results.parseError = errors.New(argToken.value)
+			results.parseError = self.errorAt(argToken.pos, errors.New(argToken.value))
 			return results
 		default:
 			panic("Unhandled argToken type")
 		}
-		// XXX - maybe don't need self.tokens ?
-		self.tokens = append(self.tokens, argToken)
 	}
 
-	// No need to wait for the goroutine to finish. The closing of the
-	// channel means the goroutine finished. umm.. what about the early returns
-	// for help and errors
-
-	// Did we find all required parameters?
-	// TODO - switchArgumants
-
 	// If there aren't enough positional arguments, check the next known argument to see if it is required
 	cmd := results.triggeredCommand
 	if len(cmd.positionalArguments) > 0 && self.numEvaluatedPositionalArguments < cmd.numRequiredPositionalArguments {
@@ -195,21 +232,123 @@ func (self *parserState) runParser(ap *ArgumentParser, argv []string) *parseResu
 		}
 	}
 
+	cmdStack := make([]*Command, len(results.ancestorCommands)+1)
+	copy(cmdStack, results.ancestorCommands)
+	cmdStack[len(cmdStack)-1] = cmd
+
 	// Propagate inherited argument values
-	if len(results.ancestorCommands) > 0 {
-		cmdStack := make([]*Command, len(results.ancestorCommands)+1)
-		copy(cmdStack, results.ancestorCommands)
-		cmdStack[len(cmdStack)-1] = cmd
-		cmdStack[0].propagateInherited(cmdStack, 0)
+	propagateInherited := func() {
+		if len(results.ancestorCommands) > 0 {
+			cmdStack[0].propagateInherited(cmdStack, 0)
+		}
+	}
+	propagateInherited()
+
+	// Take the values of arguments that were not given on the
+	// command-line from the environment, and then from the configuration
+	// file. The inherited values from each are propagated before the
+	// next is read, so a sub-command takes its value from the most
+	// important place that gives it.
+	err := ap.applyEnv(cmdStack)
+	if err != nil {
+		results.parseError = err
+		return results
+	}
+	propagateInherited()
+
+	err = ap.applyConfig(cmdStack)
+	if err != nil {
+		results.parseError = err
+		return results
+	}
+	propagateInherited()
+
+	// Did we find all required switch arguments?
+	err = checkRequiredSwitches(cmdStack)
+	if err != nil {
+		results.parseError = err
+		return results
+	}
+
+	// Are the dependencies between arguments satisfied?
+	err = checkDependencies(cmdStack)
+	if err != nil {
+		results.parseError = err
+		return results
+	}
+
+	// Are the argument groups satisfied?
+	err = checkGroups(cmdStack)
+	if err != nil {
+		results.parseError = err
+		return results
 	}
 
 	return results
 }
 
+// Parse the text into the value of the argument, check it against the
+// Choices and validators, and call the Callback. The label is how the
+// argument was given, for the error messages.
+func (self *Argument) storeValue(m *Messages, label string, text string) error {
+	err := self.loadChoices(m)
+	if err == nil {
+		err = self.checkValue(m, text)
+	}
+	if err == nil {
+		err = self.value.parse(m, text)
+	}
+	if err != nil {
+		return fmt.Errorf("While parsing value for %s: %w", label, err)
+	}
+	return runCallback(self, label)
+}
+
+// Call the Callback of an ActionCallback argument, after its value is stored
+func runCallback(arg *Argument, label string) error {
+	if arg.Action != ActionCallback {
+		return nil
+	}
+	err := arg.Callback(arg.lastValue())
+	if err != nil {
+		return fmt.Errorf("%s: %w", label, err)
+	}
+	return nil
+}
+
+// Check that all the Required switch arguments were seen, in the triggered
+// Command and its ancestors, and report all that were not.
+func checkRequiredSwitches(cmdStack []*Command) error {
+	var missing []string
+	leafIndex := len(cmdStack) - 1
+
+	for i, cmd := range cmdStack {
+		for _, arg := range cmd.switchArguments {
+			if !arg.Required {
+				continue
+			}
+			// An inherited argument may be given before or after the
+			// sub-command; its value has been propagated to the
+			// triggered Command, so it is checked there.
+			if arg.Inherit && i < leafIndex {
+				continue
+			}
+			if !cmd.Seen[arg.Dest] {
+				missing = append(missing, arg.PrettyName())
+			}
+		}
+	}
+
+	if len(missing) == 1 {
+		return fmt.Errorf("Missing required switch: %s", missing[0])
+	} else if len(missing) > 1 {
+		return fmt.Errorf("Missing required switches: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // This is the engine of the state machine
 func (self *parserState) _parse() {
-	defer close(self.tokenChan)
-
 	// Start at the initial state, and get the next state,
 	// ove and over again, entil we reach the final state (nil)
 	var state stateFunc
@@ -234,14 +373,25 @@ func (self *parserState) stateArgument() stateFunc {
 	if self.subCommandAllowed {
 		for _, subCommand := range self.cmd.subCommands {
 			if arg == subCommand.Name {
-				self.cmd.CommandSeen[arg] = true
-				self.emitParser(subCommand)
-				self.pos += 1
-				// The subparser can have its own subparsers
-				self.subCommandAllowed = len(subCommand.subCommands) > 0
-				// Start parsing in the subCommand!
-				self.cmd = subCommand
-				return self.stateArgument
+				return self.enterSubCommand(subCommand)
+			}
+		}
+		if self.ap.AbbreviateSubCommands {
+			var matches []*Command
+			var names []string
+			for _, subCommand := range self.cmd.subCommands {
+				if strings.HasPrefix(subCommand.Name, arg) {
+					matches = append(matches, subCommand)
+					names = append(names, subCommand.Name)
+				}
+			}
+			if len(matches) == 1 {
+				return self.enterSubCommand(matches[0])
+			} else if len(matches) > 1 {
+				self.emitWithValue(tokError,
+					fmt.Sprintf("Ambiguous command %s could match %s",
+						arg, strings.Join(names, ", ")))
+				return nil
 			}
 		}
 	}
@@ -262,6 +412,77 @@ func (self *parserState) stateArgument() stateFunc {
 	return nil
 }
 
+// Find the Negatable argument that has the text as a negated switch
+func (self *parserState) findNegatedSwitch(text string) *Argument {
+	for _, arg := range self.cmd.switchArguments {
+		if arg.isNegatedSwitch(text) {
+			return arg
+		}
+	}
+	return nil
+}
+
+func (self *parserState) enterSubCommand(subCommand *Command) stateFunc {
+	self.cmd.CommandSeen[subCommand.Name] = true
+	self.emitParser(subCommand)
+	self.pos += 1
+	// The subparser can have its own subparsers
+	self.subCommandAllowed = len(subCommand.subCommands) > 0
+	// Start parsing in the subCommand!
+	self.cmd = subCommand
+	return self.stateArgument
+}
+
+// Find the long switch that text is a unique abbreviation of. If it
+// abbreviates one of the HelpSwitches, the returned Argument is nil
+// but the returned switch name is not empty. If it abbreviates
+// nothing, both are empty.
+func (self *parserState) expandAbbreviation(text string) (*Argument, string, error) {
+	var matches []string
+	var numMatchedArguments int
+	var matchedArg *Argument
+	var matchedSwitch string
+
+	if self.cmd.abbreviationsAllowed() {
+		for _, arg := range self.cmd.switchArguments {
+			argMatched := false
+			possibilities := append(arg.negatedSwitches(), arg.Switches...)
+			for _, possibility := range possibilities {
+				if strings.HasPrefix(possibility, "--") && strings.HasPrefix(possibility, text) {
+					matches = append(matches, possibility)
+					if !argMatched {
+						argMatched = true
+						numMatchedArguments++
+						matchedArg = arg
+						matchedSwitch = possibility
+					}
+				}
+			}
+		}
+	}
+
+	if self.ap.AbbreviateHelpSwitches {
+		helpMatched := false
+		for _, hw := range self.ap.HelpSwitches {
+			if strings.HasPrefix(hw, "--") && strings.HasPrefix(hw, text) {
+				matches = append(matches, hw)
+				if !helpMatched {
+					helpMatched = true
+					numMatchedArguments++
+					matchedArg = nil
+					matchedSwitch = hw
+				}
+			}
+		}
+	}
+
+	if numMatchedArguments > 1 {
+		return nil, "", fmt.Errorf("Ambiguous option %s could match %s",
+			text, strings.Join(matches, ", "))
+	}
+	return matchedArg, matchedSwitch, nil
+}
+
 func (self *parserState) stateMaybeOneValue() stateFunc {
 	if self.pos == len(self.args) {
 		// Fine, we're finished.
@@ -364,87 +585,66 @@ func (self *parserState) stateSwitchArgument() stateFunc {
 				match = true
 				break
 			}
-			/*
-				// We could still have -j4, which is a short option
-				// with an adjoining number; this is only valid for short options
-				// with  numeric arguments
-				if arg.typeKind == reflect.Int &&		// dest is an Int
-					text[1] != '-' &&			// short option
-					rhs == "" &&				// There wasn't an =
-					len(possibility) < len(text) &&
-					text[:len(possibility)] == possibility {
-
-					rhs = text[len(possibility):]
-					text = text[:len(possibility)]
-					match = true
-					break
-				}
-				// TODO - this might be too early to do this
-				// Or we could have a group of short booleans IFF the option name is
-				// onlyone character long; if -x is a boolean
-				// and -y is a boolan, than -xy (and -yx) are valid
-				if arg.typeKind == reflect.Bool &&		// dest is an Boolean
-					len(possibility) == 2 &&		// switch is 2 chars long
-					text[1] != '-' &&			// short option given
-					rhs == "" &&				// There wasn't an =
-					text[:2] == possibility {
-
-					// Emit this one
-					self.emitWithArgument(tokArgument, arg, text[:2])
-					self.lastSwitch = text[:2]
-
-					// All other characters in the given switch must also be one-character
-					// short-option booleans
-					// TODO- think about utf-8 here
-					all_others_good := true
-					for _, r := range text[2:] {
-						found := false
-						for _, iArg := range self.cmd.switchArguments {
-							if iArg.NumArgs == numArgs0 {
-								for _, iSwitch := range iArg.Switches {
-									if len(iSwitch) == 2 && rune(iSwitch[1]) == r {
-										found = true
-										// Emit this one
-										self.emitWithArgument(tokArgument, iArg, iSwitch)
-										self.lastSwitch = iSwitch
-										break
-									}
-								}
-							}
-							if found {
-								break
-							}
-						}
-						if ! found {
-							all_others_good = false
-							break
-						}
-					}
-					if !all_others_good {
-						self.emitWithValue(tokError,
-							fmt.Sprintf("The %s switch is valid but not as '%s'",
-								text[:2], text))
-						return nil
-					}
-
-					// We finished the parse and need to return successfully now
-					self.pos += 1
-					return self.stateArgument
-				}
-			*/
 		}
 
 		if match {
 			break
 		}
 	}
+
+	// Is it the negation of a Negatable switch, like --no-verbose?
+	if !match {
+		arg = self.findNegatedSwitch(text)
+		match = arg != nil
+	}
+
+	// Is it an abbreviation of a long switch?
+	if !match && strings.HasPrefix(text, "--") {
+		expandedArg, expandedSwitch, err := self.expandAbbreviation(text)
+		if err != nil {
+			self.emitWithValue(tokError, err.Error())
+			return nil
+		}
+		if expandedArg == nil && expandedSwitch != "" {
+			// It's a help switch
+			if rhs == "" {
+				self.emitToken(tokHelp)
+			} else {
+				self.emitWithValue(tokError, expandedSwitch+" does not accept a value")
+			}
+			return nil
+		} else if expandedArg != nil {
+			arg = expandedArg
+			text = expandedSwitch
+			match = true
+		}
+	}
+
 	// Didn't match ?
 	if !match {
+		// Could it be a group of short switches, like -xvf, or a short
+		// switch with an attached value, like -j4 ?
+		original := self.args[self.pos]
+		if len(original) > 2 && original[1] != '-' {
+			return self.stateShortSwitchGroup
+		}
 		// Didn't find a switch with that name
 		self.emitWithValue(tokError, fmt.Sprintf("No such switch: %s", text))
 		return nil
 	}
 
+	if arg.isNegatedSwitch(text) {
+		if rhs != "" {
+			self.emitWithValue(tokError,
+				fmt.Sprintf("The %s switch does not take a value", text))
+			return nil
+		}
+		self.emitWithArgument(tokNegatedArgument, arg, text)
+		self.lastSwitch = text
+		self.pos += 1
+		return self.stateArgument
+	}
+
 	self.emitWithArgument(tokArgument, arg, text)
 	self.lastSwitch = text
 	if rhs == "" {
@@ -483,7 +683,83 @@ func (self *parserState) stateSwitchArgument() stateFunc {
 			panic(fmt.Sprintf("Unexpected num args: %v", arg.NumArgs))
 		}
 	}
-	panic("not reached")
+}
+
+// Handle a group of single-character switches given together, as in "-xvf",
+// or a single-character switch with its value attached, as in "-j4"
+// or "-ofile". A switch that takes a value can only be the first one in
+// the group, in which case the rest of the text is its value, or the last
+// one, in which case its value comes from the next argument.
+func (self *parserState) stateShortSwitchGroup() stateFunc {
+	text := self.args[self.pos]
+	letters := []rune(text[1:])
+
+	for i, letter := range letters {
+		label := "-" + string(letter)
+
+		for _, hw := range self.ap.HelpSwitches {
+			if label == hw {
+				self.emitToken(tokHelp)
+				return nil
+			}
+		}
+
+		var arg *Argument
+		for _, possibleArg := range self.cmd.switchArguments {
+			for _, possibility := range possibleArg.Switches {
+				if label == possibility {
+					arg = possibleArg
+					break
+				}
+			}
+			if arg != nil {
+				break
+			}
+		}
+		if arg == nil {
+			if i == 0 {
+				self.emitWithValue(tokError, fmt.Sprintf("No such switch: %s", text))
+			} else {
+				self.emitWithValue(tokError,
+					fmt.Sprintf("No such switch: %s (in %s)", label, text))
+			}
+			return nil
+		}
+
+		self.emitWithArgument(tokArgument, arg, label)
+		self.lastSwitch = label
+		if arg.NumArgs == 0 {
+			continue
+		}
+
+		rest := string(letters[i+1:])
+		if i == 0 {
+			// The rest of the text is the value
+			self.emitWithValue(tokValue, rest)
+			self.pos += 1
+			if arg.NumArgs > 1 {
+				self.needNValues = arg.NumArgs - 1
+				return self.stateMultipleValues
+			}
+			return self.stateArgument
+		} else if rest != "" {
+			self.emitWithValue(tokError,
+				fmt.Sprintf("The %s switch needs a value, so it cannot be in the middle of %s",
+					label, text))
+			return nil
+		}
+
+		// The last switch in the group; the value comes next
+		self.pos += 1
+		if arg.NumArgs == 1 {
+			return self.stateOneValue
+		}
+		self.needNValues = arg.NumArgs
+		return self.stateMultipleValues
+	}
+
+	self.pos += 1
+	return self.stateArgument
 }
 
 func (self *parserState) statePositionalArgument() stateFunc {
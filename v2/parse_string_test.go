@@ -0,0 +1,45 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestParseString(c *C) {
+	opts, ap := createResponseTestParser()
+	ap.AllowResponseFiles = false
+	result, err := ap.ParseString(`--name "John \"Jack\" Smith" --count=3 'a b.txt' c\ d.txt $HOME *.txt`)
+	c.Assert(err, IsNil)
+	c.Check(result.Command, Equals, ap.Root)
+	c.Check(opts.Name, Equals, `John "Jack" Smith`)
+	c.Check(opts.Count, Equals, 3)
+	c.Check(opts.Files, DeepEquals, []string{"a b.txt", "c d.txt", "$HOME", "*.txt"})
+	c.Check(ap.Root.Source("Count").Position, Equals, 2)
+}
+
+func (s *MySuite) TestParseStringErrors(c *C) {
+	_, ap := createResponseTestParser()
+	result, err := ap.ParseString(`--name 'John Smith`)
+	c.Check(result.Command, Equals, ap.Root)
+	c.Assert(err, FitsTypeOf, &ParseError{})
+	c.Check(err, ErrorMatches, `At column 8: The ' quote is not closed`)
+
+	_, ap = createResponseTestParser()
+	_, err = ap.ParseString(`a.txt "b.txt" "c.txt`)
+	c.Check(err, ErrorMatches, `At column 15: The " quote is not closed`)
+
+	_, ap = createResponseTestParser()
+	_, err = ap.ParseString("-x\n-f \"abc")
+	c.Check(err, ErrorMatches, `At line 2, column 4: The " quote is not closed`)
+
+	// Errors from the parser are the same as from ParseArgs
+	_, ap = createResponseTestParser()
+	_, err = ap.ParseString(`--count "many"`)
+	c.Assert(err, FitsTypeOf, &ParseError{})
+	c.Check(err, ErrorMatches, `While parsing value for --count: Cannot convert "many" to an integer: .*`)
+
+	_, ap = createResponseTestParser()
+	_, err = ap.ParseString(`--help`)
+	c.Check(err, FitsTypeOf, &HelpRequestedError{})
+}
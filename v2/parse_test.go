@@ -3,6 +3,7 @@ package argparse
 // Copyright (c) 2017 by Gilbert Ramirez <gram@alumni.rice.edu>
 
 import (
+	"runtime"
 	"time"
 
 	. "gopkg.in/check.v1"
@@ -614,6 +615,54 @@ func (s *MySuite) TestRootPositionalTimeDurationSlice2(c *C) {
 	c.Check(ap.Root.Seen["PosDurationSlice"], Equals, true)
 }
 
+func (s *MySuite) TestRootPositionalTimeDurationDaysWeeks(c *C) {
+	opts, ap := createPTestParser()
+
+	ap.Add(&Argument{
+		Name:    "PosDurationSlice",
+		NumArgs: 4,
+	})
+
+	argv := []string{"7d", "2w", "1d12h", "-1.5d"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Assert(len(opts.PosDurationSlice), Equals, 4)
+	c.Check(opts.PosDurationSlice[0], Equals, 7*24*time.Hour)
+	c.Check(opts.PosDurationSlice[1], Equals, 14*24*time.Hour)
+	c.Check(opts.PosDurationSlice[2], Equals, 36*time.Hour)
+	c.Check(opts.PosDurationSlice[3], Equals, -36*time.Hour)
+}
+
+func (s *MySuite) TestRootPositionalTimeDurationBad(c *C) {
+	_, ap := createPTestParser()
+
+	ap.Add(&Argument{
+		Name: "PosDurationSlice",
+	})
+
+	argv := []string{"3dx"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for PosDurationSlice: Cannot parse "3dx" as a time duration: time: unknown unit "dx" in duration "3dx"`)
+}
+
+func (s *MySuite) TestRootPositionalTimeDurationTooLong(c *C) {
+	for _, text := range []string{"106751d23h59m59s", "1d2562047h1d2562047h", "106752d"} {
+		_, ap := createPTestParser()
+
+		ap.Add(&Argument{
+			Name: "PosDurationSlice",
+		})
+
+		results := ap.parseArgv([]string{text})
+		c.Check(results.parseError, ErrorMatches,
+			`While parsing value for PosDurationSlice: Cannot parse "`+text+
+				`" as a time duration: duration "`+text+`" is too long`)
+	}
+}
+
 // ====================================================== NumArgsGlob +
 func (s *MySuite) TestNumArgsGlobPlusZero(c *C) {
 	_, ap := createPTestParser()
@@ -1078,3 +1127,478 @@ func (s *MySuite) TestRequired03(c *C) {
 	c.Check(len(opts.PosStringSlice), Equals, 1)
 	c.Check(opts.PosStringSlice[0], Equals, "x")
 }
+
+// Parsing must not leave anything running in the background, even when
+// the parse stops early because of an error or a request for help.
+func (s *MySuite) TestParseDoesNotLeakGoroutines(c *C) {
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 1000; i++ {
+		_, ap := createPTestParser()
+		results := ap.parseArgv([]string{"--int1", "abc", "--bool1", "--bool2"})
+		c.Assert(results.parseError, NotNil)
+
+		results = ap.parseArgv([]string{"--bool1", "--no-such", "--bool2"})
+		c.Assert(results.parseError, NotNil)
+
+		results = ap.parseArgv([]string{"-h", "--bool1", "--bool2"})
+		c.Assert(results.helpRequested, Equals, true)
+	}
+
+	c.Check(runtime.NumGoroutine() <= before, Equals, true)
+}
+
+// ====================================================== short switch groups
+
+type STestOptions struct {
+	X bool
+	V bool
+	F string
+	J int
+	O string
+	D []string
+}
+
+func createSTestParser() (*STestOptions, *ArgumentParser) {
+	opts := &STestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-x"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-f"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-j"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-o"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-D"},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestShortSwitchGroupBools(c *C) {
+	opts, ap := createSTestParser()
+
+	argv := []string{"-xv"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.X, Equals, true)
+	c.Check(opts.V, Equals, true)
+	c.Check(ap.Root.Seen["X"], Equals, true)
+	c.Check(ap.Root.Seen["V"], Equals, true)
+}
+
+func (s *MySuite) TestShortSwitchGroupLastTakesValue(c *C) {
+	opts, ap := createSTestParser()
+
+	argv := []string{"-xvf", "archive.tar"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.X, Equals, true)
+	c.Check(opts.V, Equals, true)
+	c.Check(opts.F, Equals, "archive.tar")
+}
+
+func (s *MySuite) TestShortSwitchGroupLastMissingValue(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-xvf"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "Expected a value after -f")
+}
+
+func (s *MySuite) TestShortSwitchAttachedInt(c *C) {
+	opts, ap := createSTestParser()
+
+	argv := []string{"-j4", "-x"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.J, Equals, 4)
+	c.Check(opts.X, Equals, true)
+}
+
+func (s *MySuite) TestShortSwitchAttachedString(c *C) {
+	opts, ap := createSTestParser()
+
+	argv := []string{"-ofile", "-Dkey=value", "-Dother=x"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.O, Equals, "file")
+	c.Check(opts.D, DeepEquals, []string{"key=value", "other=x"})
+}
+
+func (s *MySuite) TestShortSwitchAttachedBadInt(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-jx"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "While parsing value for -j: .*")
+}
+
+func (s *MySuite) TestShortSwitchGroupUnknown(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-xqv"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, `No such switch: -q \(in -xqv\)`)
+}
+
+func (s *MySuite) TestShortSwitchGroupUnknownFirst(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-qxv"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "No such switch: -qxv")
+}
+
+func (s *MySuite) TestShortSwitchGroupValueInMiddle(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-xfv", "archive.tar"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		"The -f switch needs a value, so it cannot be in the middle of -xfv")
+}
+
+func (s *MySuite) TestShortSwitchGroupHelp(c *C) {
+	_, ap := createSTestParser()
+
+	argv := []string{"-xh"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.helpRequested, Equals, true)
+}
+
+// ====================================================== abbreviations
+
+type ATestOptions struct {
+	Verbose bool
+	Version bool
+	Output  string
+}
+
+func createATestParser() (*ATestOptions, *ArgumentParser) {
+	opts := &ATestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.AllowAbbreviations = true
+	ap.Add(&Argument{
+		Switches: []string{"--verbose"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--version"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-o", "--output"},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestAbbreviationUnique(c *C) {
+	opts, ap := createATestParser()
+
+	argv := []string{"--verb", "--out", "file"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbose, Equals, true)
+	c.Check(opts.Version, Equals, false)
+	c.Check(opts.Output, Equals, "file")
+}
+
+func (s *MySuite) TestAbbreviationWithEquals(c *C) {
+	opts, ap := createATestParser()
+
+	argv := []string{"--o=file"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Output, Equals, "file")
+}
+
+func (s *MySuite) TestAbbreviationAmbiguous(c *C) {
+	_, ap := createATestParser()
+
+	argv := []string{"--ver"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		"Ambiguous option --ver could match --verbose, --version")
+}
+
+func (s *MySuite) TestAbbreviationNotAllowed(c *C) {
+	_, ap := createATestParser()
+	ap.AllowAbbreviations = false
+
+	argv := []string{"--verb"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "No such switch: --verb")
+}
+
+func (s *MySuite) TestAbbreviationCommandOverride(c *C) {
+	_, ap := createATestParser()
+	ap.Root.AllowAbbreviations = AbbreviationsNotAllowed
+
+	argv := []string{"--verb"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "No such switch: --verb")
+
+	ap.AllowAbbreviations = false
+	ap.Root.AllowAbbreviations = AbbreviationsAllowed
+
+	results = ap.parseArgv(argv)
+	c.Check(results.parseError, IsNil)
+}
+
+func (s *MySuite) TestAbbreviationHelp(c *C) {
+	_, ap := createATestParser()
+
+	argv := []string{"--he"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "No such switch: --he")
+
+	ap.AbbreviateHelpSwitches = true
+	results = ap.parseArgv(argv)
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.helpRequested, Equals, true)
+}
+
+func (s *MySuite) TestAbbreviationSubCommand(c *C) {
+	_, ap := createATestParser()
+	openCmd := ap.New(&Command{
+		Name:   "open",
+		Values: &ATestOptions{},
+	})
+	ap.New(&Command{
+		Name:   "options",
+		Values: &ATestOptions{},
+	})
+	closeCmd := ap.New(&Command{
+		Name:   "close",
+		Values: &ATestOptions{},
+	})
+
+	results := ap.parseArgv([]string{"cl"})
+	c.Check(results.parseError, ErrorMatches, "Unexpected argument: cl")
+
+	ap.AbbreviateSubCommands = true
+	results = ap.parseArgv([]string{"cl"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.triggeredCommand, Equals, closeCmd)
+
+	results = ap.parseArgv([]string{"open"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.triggeredCommand, Equals, openCmd)
+
+	results = ap.parseArgv([]string{"op"})
+	c.Check(results.parseError, ErrorMatches,
+		"Ambiguous command op could match open, options")
+}
+
+// ====================================================== required switches
+
+func (s *MySuite) TestRequiredSwitchGiven(c *C) {
+	opts, ap := createPTestParser()
+	ap.Add(&Argument{
+		Switches: []string{"--strings"},
+		Dest:     "StringSlice",
+		Required: true,
+	})
+
+	argv := []string{"--strings", "abc"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.StringSlice, DeepEquals, []string{"abc"})
+}
+
+func (s *MySuite) TestRequiredSwitchMissing(c *C) {
+	_, ap := createPTestParser()
+	ap.Add(&Argument{
+		Switches: []string{"--strings", "-s"},
+		Dest:     "StringSlice",
+		Required: true,
+	})
+
+	argv := []string{"--bool1"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --strings/-s")
+}
+
+func (s *MySuite) TestRequiredSwitchesMissing(c *C) {
+	_, ap := createPTestParser()
+	ap.Add(&Argument{
+		Switches: []string{"--strings"},
+		Dest:     "StringSlice",
+		Required: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--pos-int"},
+		Required: true,
+	})
+
+	argv := []string{}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		"Missing required switches: --strings, --pos-int")
+}
+
+func (s *MySuite) TestRequiredSwitchHelp(c *C) {
+	_, ap := createPTestParser()
+	ap.Add(&Argument{
+		Switches: []string{"--strings"},
+		Dest:     "StringSlice",
+		Help:     "Some strings",
+		Required: true,
+	})
+
+	// Help is given even when required switches are missing
+	results := ap.parseArgv([]string{"-h"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.helpRequested, Equals, true)
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--strings=STRINGS +Some strings \(required\).*`)
+	// Producing the help does not alter the switches
+	c.Check(ap.helpString(ap.Root, nil), Equals, help)
+}
+
+func (s *MySuite) TestRequiredPositionalPanics(c *C) {
+	_, ap := createPTestParser()
+	c.Check(func() {
+		ap.Add(&Argument{
+			Name:     "pos-int",
+			Required: true,
+		})
+	}, PanicMatches, "Positional argument pos-int cannot be Required.*")
+}
+
+// ====================================================== negatable switches
+
+type NTestOptions struct {
+	Color   bool
+	Verbose bool
+}
+
+func createNTestParser() (*NTestOptions, *ArgumentParser) {
+	opts := &NTestOptions{
+		Color: true,
+	}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches:  []string{"-c", "--color"},
+		Help:      "Use colors",
+		Negatable: true,
+	})
+	ap.Add(&Argument{
+		Switches:  []string{"--verbose"},
+		Negatable: true,
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestNegatableOff(c *C) {
+	opts, ap := createNTestParser()
+
+	argv := []string{"--no-color"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Color, Equals, false)
+	c.Check(ap.Root.Seen["Color"], Equals, true)
+}
+
+func (s *MySuite) TestNegatableLastWins(c *C) {
+	opts, ap := createNTestParser()
+
+	argv := []string{"--no-color", "--verbose", "-c", "--no-verbose"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Color, Equals, true)
+	c.Check(opts.Verbose, Equals, false)
+}
+
+func (s *MySuite) TestNegatableWithValue(c *C) {
+	_, ap := createNTestParser()
+
+	argv := []string{"--no-color=true"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches, "The --no-color switch does not take a value")
+}
+
+func (s *MySuite) TestNegatableAbbreviation(c *C) {
+	opts, ap := createNTestParser()
+	ap.AllowAbbreviations = true
+
+	argv := []string{"--no-c"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Color, Equals, false)
+}
+
+func (s *MySuite) TestNegatableHelp(c *C) {
+	_, ap := createNTestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*-c,--\[no-\]color +Use colors.*`)
+}
+
+func (s *MySuite) TestNegatableNonBoolPanics(c *C) {
+	_, ap := createPTestParser()
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches:  []string{"--pos-string"},
+			Negatable: true,
+		})
+	}, PanicMatches, "Argument --pos-string is Negatable but its destination is not a bool")
+}
+
+func (s *MySuite) TestNegatableDuplicatePanics(c *C) {
+	opts := &PTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches:  []string{"--bool1"},
+		Negatable: true,
+	})
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--no-bool1"},
+			Dest:     "Bool2",
+		})
+	}, PanicMatches, "--no-bool1 is already used by a switch argument in this Command.")
+}
@@ -0,0 +1,123 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements response files: an argument like @args.txt is
+// replaced by the arguments in the file.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Where an argument came from: a response file and line, or the
+// command-line, if the file is ""
+type argOrigin struct {
+	file string
+	line int
+}
+
+// Replace each @file argument with the arguments read from the file,
+// which can have @file arguments of its own. An argument starting with
+// @@ is kept, without the first @. Returns the arguments, and where each
+// of them came from.
+func (self *ArgumentParser) expandResponseFiles(argv []string) ([]string, []argOrigin, error) {
+	args := []string{}
+	origins := []argOrigin{}
+	for _, arg := range argv {
+		var err error
+		args, origins, err = self.expandResponseArg(args, origins, arg, argOrigin{}, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return args, origins, nil
+}
+
+// Append one argument, or the arguments of the response file that it
+// names, to args. The stack has the response files being read, to find
+// a file that includes itself.
+func (self *ArgumentParser) expandResponseArg(args []string, origins []argOrigin,
+	arg string, origin argOrigin, stack []string) ([]string, []argOrigin, error) {
+
+	if !strings.HasPrefix(arg, "@") || arg == "@" {
+		return append(args, arg), append(origins, origin), nil
+	}
+	if strings.HasPrefix(arg, "@@") {
+		return append(args, arg[1:]), append(origins, origin), nil
+	}
+
+	filename := arg[1:]
+	words, err := self.readResponseFile(filename, stack)
+	if err != nil {
+		// Say which response file had the @file argument
+		if origin.file != "" {
+			err = fileLineError(origin.file, origin.line, err)
+		}
+		return nil, nil, err
+	}
+	stack = append(stack[:len(stack):len(stack)], filename)
+	for _, word := range words {
+		args, origins, err = self.expandResponseArg(args, origins, word.text,
+			argOrigin{file: filename, line: word.line}, stack)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return args, origins, nil
+}
+
+// Read the words of a response file
+func (self *ArgumentParser) readResponseFile(filename string, stack []string) ([]shellWord, error) {
+	err := self.checkResponseFileCycle(filename, stack)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(self.Messages.CannotReadResponseFileFmt, err)
+	}
+	words, err := splitShellWords(&self.Messages, string(data))
+	if err != nil {
+		splitErr := err.(*shellSplitError)
+		return nil, fileLineError(filename, splitErr.line, splitErr.err)
+	}
+	return words, nil
+}
+
+// Is the response file already being read?
+func (self *ArgumentParser) checkResponseFileCycle(filename string, stack []string) error {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf(self.Messages.CannotReadResponseFileFmt, err)
+	}
+	for _, other := range stack {
+		absOther, err := filepath.Abs(other)
+		if err == nil && absOther == absFilename {
+			return fmt.Errorf(self.Messages.ResponseFileCycleFmt, filename)
+		}
+	}
+	return nil
+}
+
+// Add the response file and line that an argument came from to an error.
+// A pos past the end of the arguments is for the last argument.
+func (self *parserState) errorAt(pos int, err error) error {
+	if pos >= len(self.origins) {
+		pos = len(self.origins) - 1
+	}
+	if pos < 0 || self.origins[pos].file == "" {
+		return err
+	}
+	return fileLineError(self.origins[pos].file, self.origins[pos].line, err)
+}
+
+// The response file and line that an argument came from, if any
+func (self *parserState) origin(pos int) argOrigin {
+	if pos < 0 || pos >= len(self.origins) {
+		return argOrigin{}
+	}
+	return self.origins[pos]
+}
@@ -0,0 +1,110 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+type ResponseTestOptions struct {
+	Count int
+	Name  string
+	Files []string
+}
+
+func createResponseTestParser() (*ResponseTestOptions, *ArgumentParser) {
+	opts := &ResponseTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.AllowResponseFiles = true
+	ap.Add(&Argument{
+		Switches: []string{"--count"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--name"},
+	})
+	ap.Add(&Argument{
+		Name:        "files",
+		NumArgsGlob: "*",
+	})
+	return opts, ap
+}
+
+func writeResponseFile(c *C, dir string, name string, text string) string {
+	filename := filepath.Join(dir, name)
+	err := ioutil.WriteFile(filename, []byte(text), 0644)
+	c.Assert(err, IsNil)
+	return filename
+}
+
+func (s *MySuite) TestResponseFiles(c *C) {
+	dir := c.MkDir()
+	inner := writeResponseFile(c, dir, "inner.txt", "c.txt\n@@d.txt\n")
+	outer := writeResponseFile(c, dir, "outer.txt", `# The options
+--count 3
+--name 'John Smith'
+a.txt
+@`+inner+`
+`)
+
+	opts, ap := createResponseTestParser()
+	results := ap.parseArgv([]string{"@" + outer, "b.txt", "@@e.txt", "@"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Count, Equals, 3)
+	c.Check(opts.Name, Equals, "John Smith")
+	c.Check(opts.Files, DeepEquals, []string{"a.txt", "c.txt", "@d.txt", "b.txt", "@e.txt", "@"})
+	c.Check(ap.Root.Source("Name"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "--name",
+		Position: 2,
+		File:     outer,
+		Line:     3,
+	})
+
+	// Without AllowResponseFiles, the arguments are kept
+	opts, ap = createResponseTestParser()
+	ap.AllowResponseFiles = false
+	results = ap.parseArgv([]string{"@" + outer, "@@e.txt"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Files, DeepEquals, []string{"@" + outer, "@@e.txt"})
+}
+
+func (s *MySuite) TestResponseFileErrors(c *C) {
+	dir := c.MkDir()
+	values := writeResponseFile(c, dir, "values.txt", "--name x\n\n--count lots\n")
+	_, ap := createResponseTestParser()
+	results := ap.parseArgv([]string{"@" + values})
+	c.Check(results.parseError, ErrorMatches,
+		`.*values.txt:3: While parsing value for --count: Cannot convert "lots" to an integer: .*`)
+
+	missingValue := writeResponseFile(c, dir, "missing_value.txt", "--name x\n--count\n")
+	_, ap = createResponseTestParser()
+	results = ap.parseArgv([]string{"@" + missingValue})
+	c.Check(results.parseError, ErrorMatches,
+		`.*missing_value.txt:2: Expected a value after --count`)
+
+	quote := writeResponseFile(c, dir, "quote.txt", "--name\n'John Smith\n")
+	_, ap = createResponseTestParser()
+	results = ap.parseArgv([]string{"@" + quote})
+	c.Check(results.parseError, ErrorMatches,
+		`.*quote.txt:2: The ' quote is not closed`)
+
+	missing := filepath.Join(dir, "missing.txt")
+	including := writeResponseFile(c, dir, "including.txt", "a.txt\n@"+missing+"\n")
+	_, ap = createResponseTestParser()
+	results = ap.parseArgv([]string{"@" + including})
+	c.Check(results.parseError, ErrorMatches,
+		`.*including.txt:2: Cannot read the response file: open .*missing.txt: no such file or directory`)
+
+	cycleA := filepath.Join(dir, "a.txt")
+	cycleB := writeResponseFile(c, dir, "b.txt", "@"+cycleA+"\n")
+	writeResponseFile(c, dir, "a.txt", "x\n@"+cycleB+"\n")
+	_, ap = createResponseTestParser()
+	results = ap.parseArgv([]string{"@" + cycleA})
+	c.Check(results.parseError, ErrorMatches,
+		`.*b.txt:1: The response file .*a.txt includes itself`)
+}
@@ -0,0 +1,73 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+)
+
+// The outcome of a successful (or unsuccessful) ParseArgs
+type ParseResult struct {
+	// The Command that was triggered by the command-line; this is
+	// the Root command if no sub-command was given.
+	Command *Command
+
+	// The Commands above the triggered Command, starting with the Root.
+	Ancestors []*Command
+}
+
+// Returned when the user asked for help (-h). This is not a failure;
+// programs normally print the Help and exit with 0.
+type HelpRequestedError struct {
+	// The Command for which help was requested
+	Command *Command
+
+	// The help text for that Command
+	Help string
+}
+
+func (self *HelpRequestedError) Error() string {
+	return fmt.Sprintf("Help requested for %s", self.Command.Name)
+}
+
+// Returned when the command-line given by the user could not be parsed.
+type ParseError struct {
+	// The Command that was being parsed when the error was found
+	Command *Command
+
+	Err error
+}
+
+func (self *ParseError) Error() string {
+	return self.Err.Error()
+}
+
+func (self *ParseError) Unwrap() error {
+	return self.Err
+}
+
+// Returned by Run when the Function of the triggered Command
+// returns an error.
+type CallbackError struct {
+	// The Command whose Function was called
+	Command *Command
+
+	Err error
+}
+
+func (self *CallbackError) Error() string {
+	return self.Err.Error()
+}
+
+func (self *CallbackError) Unwrap() error {
+	return self.Err
+}
+
+// Returned by Run when the triggered Command has no Function to call.
+type NoFunctionError struct {
+	Command *Command
+}
+
+func (self *NoFunctionError) Error() string {
+	return fmt.Sprintf("Command %s has no function to run", self.Command.Name)
+}
@@ -0,0 +1,132 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements splitting text into words, the way a POSIX shell
+// does, for response files and ParseString.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A word split from the text, and where it starts
+type shellWord struct {
+	text string
+	// Starting at 1
+	line   int
+	column int
+}
+
+// An error in the text, and where it is
+type shellSplitError struct {
+	line   int
+	column int
+	err    error
+}
+
+func (self *shellSplitError) Error() string {
+	return self.err.Error()
+}
+
+func (self *shellSplitError) Unwrap() error {
+	return self.err
+}
+
+// Split the text into words. Words are separated by spaces, tabs, and
+// newlines. Single quotes keep everything between them, and double quotes
+// keep everything but the backslash escapes of ", \, $, `, and a newline.
+// Outside of quotes, a backslash keeps the next character, and
+// a backslash-newline is removed. A "#" at the start of a word starts a
+// comment, up to the end of the line.
+func splitShellWords(m *Messages, text string) ([]shellWord, error) {
+	words := []shellWord{}
+	runes := []rune(text)
+	line := 1
+	column := 1
+
+	// Move past the rune at i
+	next := func(i int) int {
+		if runes[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		return i + 1
+	}
+
+	i := 0
+	for i < len(runes) {
+		// Skip the space between words
+		if strings.ContainsRune(" \t\r\n", runes[i]) {
+			i = next(i)
+			continue
+		}
+		// Skip a comment
+		if runes[i] == '#' {
+			for i < len(runes) && runes[i] != '\n' {
+				i = next(i)
+			}
+			continue
+		}
+
+		word := shellWord{line: line, column: column}
+		var builder strings.Builder
+	wordLoop:
+		for i < len(runes) {
+			c := runes[i]
+			switch c {
+			case ' ', '\t', '\r', '\n':
+				break wordLoop
+
+			case '\\':
+				i = next(i)
+				if i == len(runes) {
+					// A trailing backslash is kept
+					builder.WriteRune(c)
+				} else {
+					if runes[i] != '\n' {
+						builder.WriteRune(runes[i])
+					}
+					i = next(i)
+				}
+
+			case '\'', '"':
+				quoteLine, quoteColumn := line, column
+				i = next(i)
+				for {
+					if i == len(runes) {
+						return nil, &shellSplitError{
+							line:   quoteLine,
+							column: quoteColumn,
+							err:    fmt.Errorf(m.UnterminatedQuoteFmt, string(c)),
+						}
+					}
+					if runes[i] == c {
+						i = next(i)
+						break
+					}
+					if c == '"' && runes[i] == '\\' && i+1 < len(runes) &&
+						strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+						i = next(i)
+						if runes[i] != '\n' {
+							builder.WriteRune(runes[i])
+						}
+						i = next(i)
+						continue
+					}
+					builder.WriteRune(runes[i])
+					i = next(i)
+				}
+
+			default:
+				builder.WriteRune(c)
+				i = next(i)
+			}
+		}
+		word.text = builder.String()
+		words = append(words, word)
+	}
+	return words, nil
+}
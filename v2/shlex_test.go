@@ -0,0 +1,52 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestSplitShellWords(c *C) {
+	tests := []struct {
+		text  string
+		words []string
+	}{
+		{"", []string{}},
+		{"  one two\tthree\n", []string{"one", "two", "three"}},
+		{`'a b' "c d" e\ f`, []string{"a b", "c d", "e f"}},
+		{`x"y z"'w'`, []string{"xy zw"}},
+		{`"" ''`, []string{"", ""}},
+		{`"a \"b\" \\ \n $"`, []string{`a "b" \ \n $`}},
+		{`'a \"b'`, []string{`a \"b`}},
+		{"one \\\ntwo", []string{"one", "two"}},
+		{"# a comment\none # another\ntwo#three", []string{"one", "two#three"}},
+		{"'multi\nline'", []string{"multi\nline"}},
+		{`C:\\dir`, []string{`C:\dir`}},
+	}
+	for _, test := range tests {
+		words, err := splitShellWords(&DefaultMessages_en, test.text)
+		c.Assert(err, IsNil, Commentf(test.text))
+		texts := []string{}
+		for _, word := range words {
+			texts = append(texts, word.text)
+		}
+		c.Check(texts, DeepEquals, test.words, Commentf(test.text))
+	}
+}
+
+func (s *MySuite) TestSplitShellWordsPositions(c *C) {
+	words, err := splitShellWords(&DefaultMessages_en, "one  two\n  'three'")
+	c.Assert(err, IsNil)
+	c.Check(words, DeepEquals, []shellWord{
+		{text: "one", line: 1, column: 1},
+		{text: "two", line: 1, column: 6},
+		{text: "three", line: 2, column: 3},
+	})
+
+	_, err = splitShellWords(&DefaultMessages_en, "one\ntwo \"three")
+	c.Assert(err, FitsTypeOf, &shellSplitError{})
+	splitErr := err.(*shellSplitError)
+	c.Check(splitErr.line, Equals, 2)
+	c.Check(splitErr.column, Equals, 5)
+	c.Check(err, ErrorMatches, `The " quote is not closed`)
+}
@@ -0,0 +1,114 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements keeping track of where the value of each
+// argument came from.
+
+// Where the value of an argument came from
+type SourceKind int
+
+const (
+	// The argument was not given, so its value is the one that was
+	// in the Values struct
+	SourceDefault SourceKind = iota
+	SourceCommandLine
+	SourceEnv
+	SourceConfigFile
+)
+
+func (self SourceKind) String() string {
+	switch self {
+	case SourceDefault:
+		return "default"
+	case SourceCommandLine:
+		return "command-line"
+	case SourceEnv:
+		return "environment"
+	case SourceConfigFile:
+		return "configuration file"
+	default:
+		return "unknown"
+	}
+}
+
+// Where the value of an argument came from, as returned by Command.Source
+type ValueSource struct {
+	Kind SourceKind
+
+	// How the argument was given: the switch or positional argument name,
+	// as the user typed it on the command-line, the environment variable,
+	// like "$MYTOOL_LOG_LEVEL", or the key in the configuration file
+	Label string
+
+	// For SourceCommandLine, the index of the last time the argument was
+	// given, in the arguments that were parsed, not counting the program
+	// name, and after response files are expanded
+	Position int
+
+	// If the value was inherited from an ancestor Command, its Name
+	InheritedFrom string
+
+	// For SourceConfigFile, the file name and line number of the key.
+	// For SourceCommandLine, the response file and line that the argument
+	// was read from, if any.
+	File string
+	Line int
+
+	// The places that also gave a value for the argument, but were not
+	// used because they are less important, like a configuration file
+	// value that was overridden on the command-line. The most important
+	// is first.
+	Shadowed []ValueSource
+}
+
+// Where the value of the argument with the Dest came from, after parsing.
+// If it was not given at all, the Kind is SourceDefault.
+func (self *Command) Source(dest string) ValueSource {
+	source, ok := self.sources[dest]
+	if !ok {
+		return ValueSource{Kind: SourceDefault}
+	}
+	return source.copy()
+}
+
+func (self ValueSource) copy() ValueSource {
+	self.Shadowed = append([]ValueSource(nil), self.Shadowed...)
+	return self
+}
+
+// Record where the value of the argument came from
+func (self *Command) setSource(arg *Argument, source ValueSource) {
+	self.sources[arg.Dest] = source
+}
+
+// Record a place that gave a value for an argument that was already seen
+func (self *Command) addShadowedSource(arg *Argument, shadowed ValueSource) {
+	source, ok := self.sources[arg.Dest]
+	if !ok {
+		return
+	}
+	// An inherited value was already seen with the same label
+	if source.Kind == shadowed.Kind && source.Label == shadowed.Label {
+		return
+	}
+	for _, other := range source.Shadowed {
+		if other.Kind == shadowed.Kind && other.Label == shadowed.Label {
+			return
+		}
+	}
+	source.Shadowed = append(source.Shadowed, shadowed)
+	self.sources[arg.Dest] = source
+}
+
+// Where an argument given on the command-line came from
+func (self *parserState) commandLineSource(token argToken) ValueSource {
+	origin := self.origin(token.pos)
+	return ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    token.argumentLabel,
+		Position: token.pos,
+		File:     origin.file,
+		Line:     origin.line,
+	}
+}
@@ -0,0 +1,124 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestSourceCommandLine(c *C) {
+	_, _, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"--workers", "3", "-v", "--log-level=info"})
+	c.Assert(results.parseError, IsNil)
+
+	c.Check(ap.Root.Source("Workers"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "--workers",
+		Position: 0,
+	})
+	c.Check(ap.Root.Source("Verbose"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "-v",
+		Position: 2,
+	})
+	c.Check(ap.Root.Source("LogLevel").Position, Equals, 3)
+	c.Check(ap.Root.Source("Names"), DeepEquals, ValueSource{
+		Kind: SourceDefault,
+	})
+	c.Check(ap.Root.Source("Names").Kind.String(), Equals, "default")
+}
+
+// Each parse has its own sources
+func (s *MySuite) TestSourceParseTwice(c *C) {
+	_, _, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"--workers", "3", "-v"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(ap.Root.Source("Verbose").Kind, Equals, SourceCommandLine)
+
+	results = ap.parseArgv([]string{"--workers", "4"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(ap.Root.Source("Verbose"), DeepEquals, ValueSource{
+		Kind: SourceDefault,
+	})
+	c.Check(ap.Root.Source("Workers"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "--workers",
+		Position: 0,
+	})
+}
+
+func (s *MySuite) TestSourceEnvAndConfig(c *C) {
+	filename := writeConfigFile(c, "tool.toml", `
+workers = 4
+log-level = "debug"
+names = ["a"]
+`)
+	_, _, ap := createConfigTestParser()
+	ap.LookupEnv = func(name string) (string, bool) {
+		switch name {
+		case "TOOL_WORKERS":
+			return "6", true
+		case "TOOL_LOG_LEVEL":
+			return "info", true
+		}
+		return "", false
+	}
+	results := ap.parseArgv([]string{"--config", filename, "--log-level", "warn"})
+	c.Assert(results.parseError, IsNil)
+
+	c.Check(ap.Root.Source("Names"), DeepEquals, ValueSource{
+		Kind:  SourceConfigFile,
+		Label: "names",
+		File:  filename,
+		Line:  4,
+	})
+	c.Check(ap.Root.Source("Workers"), DeepEquals, ValueSource{
+		Kind:  SourceEnv,
+		Label: "$TOOL_WORKERS",
+		Shadowed: []ValueSource{
+			{Kind: SourceConfigFile, Label: "workers", File: filename, Line: 2},
+		},
+	})
+	c.Check(ap.Root.Source("LogLevel"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "--log-level",
+		Position: 2,
+		Shadowed: []ValueSource{
+			{Kind: SourceEnv, Label: "$TOOL_LOG_LEVEL"},
+			{Kind: SourceConfigFile, Label: "log-level", File: filename, Line: 3},
+		},
+	})
+}
+
+func (s *MySuite) TestSourceInherited(c *C) {
+	filename := writeConfigFile(c, "tool.toml", `
+workers = 4
+log-level = "debug"
+`)
+	_, _, ap := createConfigTestParser()
+	results := ap.parseArgv([]string{"--config", filename, "--log-level", "warn", "run"})
+	c.Assert(results.parseError, IsNil)
+
+	run := results.triggeredCommand
+	c.Check(run.Source("LogLevel"), DeepEquals, ValueSource{
+		Kind:          SourceCommandLine,
+		Label:         "--log-level",
+		Position:      2,
+		InheritedFrom: ap.Root.Name,
+		Shadowed: []ValueSource{
+			{Kind: SourceConfigFile, Label: "log-level", File: filename, Line: 3},
+		},
+	})
+	c.Check(run.Source("Config").InheritedFrom, Equals, ap.Root.Name)
+	c.Check(run.Source("Force").Kind, Equals, SourceDefault)
+
+	// A value given to the sub-command itself is not inherited
+	_, _, ap = createConfigTestParser()
+	results = ap.parseArgv([]string{"--workers", "1", "run", "--log-level", "info"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(results.triggeredCommand.Source("LogLevel"), DeepEquals, ValueSource{
+		Kind:     SourceCommandLine,
+		Label:    "--log-level",
+		Position: 3,
+	})
+}
@@ -0,0 +1,214 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements defining arguments with struct tags on the
+// fields of the Values struct.
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Add an Argument for each field of the Values struct that has an
+// "argparse" tag, in the order of the fields. The tags are:
+//
+//	argparse:"-v,--verbose"  the switches, or the name of a positional
+//	                         argument; "-" skips the field
+//	help:"..."               Help
+//	metavar:"N"              MetaVar
+//	env:"MYTOOL_N"           Env
+//	choices:"a,b"            Choices, parsed as values of the field's type
+//	inherit:"true"           Inherit
+//	required:"true"          Required
+//	negatable:"true"         Negatable
+//	nargs:"2" or nargs:"+"   NumArgs, or NumArgsGlob
+//
+// A field that is a nested struct, with a tag like prefix:"db.", has its
+// own tagged fields, whose long switches start with the prefix, as in
+// "--db.host". The Dest of each is the path to the field, like "DB.Host".
+//
+// For example:
+//
+//	type MyOptions struct {
+//		Verbose bool     `argparse:"-v,--verbose" help:"Be chatty"`
+//		Names   []string `argparse:"names" nargs:"+" help:"The names"`
+//	}
+func (self *Command) AddFromStruct() {
+	if self.Values == nil {
+		panic(fmt.Sprintf("There is no Values field set for Command %s", self.Name))
+	}
+	structValue := reflect.Indirect(reflect.ValueOf(self.Values))
+	if structValue.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("The Values for Command %s is not a pointer to a struct",
+			self.Name))
+	}
+	self.addFromStructFields(structValue.Type(), "", "")
+}
+
+// Add the Arguments for the fields of a struct, which may be nested in
+// the Values struct. The Dest of each Argument starts with destPrefix,
+// and each of its long switches, after the "--", with switchPrefix.
+func (self *Command) addFromStructFields(structType reflect.Type, destPrefix, switchPrefix string) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		// A nested struct with a prefix tag has its own tagged fields
+		if prefix, ok := field.Tag.Lookup("prefix"); ok {
+			if field.Type.Kind() != reflect.Struct {
+				panic(fmt.Sprintf("Field %s of %s has a prefix tag but is not a struct",
+					field.Name, structType.String()))
+			}
+			self.addFromStructFields(field.Type, destPrefix+field.Name+".",
+				switchPrefix+prefix)
+			continue
+		}
+
+		names, ok := field.Tag.Lookup("argparse")
+		if !ok || names == "-" {
+			continue
+		}
+		arg, err := argumentFromTags(field, &self.ap.Messages)
+		if err != nil {
+			panic(fmt.Sprintf("Field %s of %s: %s", field.Name, structType.String(), err))
+		}
+		arg.Dest = destPrefix + arg.Dest
+		for j, switchName := range arg.Switches {
+			if strings.HasPrefix(switchName, "--") {
+				arg.Switches[j] = "--" + switchPrefix + switchName[2:]
+			}
+		}
+		self.Add(arg)
+	}
+}
+
+// Create the Argument for a field from its tags
+func argumentFromTags(field reflect.StructField, m *Messages) (*Argument, error) {
+	arg := &Argument{
+		Help:    field.Tag.Get("help"),
+		MetaVar: field.Tag.Get("metavar"),
+		Env:     field.Tag.Get("env"),
+		Dest:    field.Name,
+	}
+
+	names := field.Tag.Get("argparse")
+	if names == "" {
+		// Derive the switch from the field name
+		arg.Switches = []string{"--" + switchNameFromField(field.Name)}
+	} else if strings.HasPrefix(names, "-") {
+		for _, switchName := range strings.Split(names, ",") {
+			arg.Switches = append(arg.Switches, strings.TrimSpace(switchName))
+		}
+	} else {
+		arg.Name = names
+	}
+
+	var err error
+	for _, flag := range []struct {
+		tag   string
+		value *bool
+	}{
+		{"inherit", &arg.Inherit},
+		{"required", &arg.Required},
+		{"negatable", &arg.Negatable},
+	} {
+		text, ok := field.Tag.Lookup(flag.tag)
+		if !ok {
+			continue
+		}
+		*flag.value, err = strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s tag \"%s\"", flag.tag, text)
+		}
+	}
+
+	if nargs, ok := field.Tag.Lookup("nargs"); ok {
+		switch nargs {
+		case "+", "*", "?":
+			arg.NumArgsGlob = nargs
+		default:
+			arg.NumArgs, err = strconv.Atoi(nargs)
+			if err != nil || arg.NumArgs < 1 {
+				return nil, fmt.Errorf("bad nargs tag \"%s\"", nargs)
+			}
+		}
+	}
+	if choices, ok := field.Tag.Lookup("choices"); ok {
+		texts := strings.Split(choices, ",")
+		for i, text := range texts {
+			texts[i] = strings.TrimSpace(text)
+		}
+		arg.Choices, err = choicesFromStrings(field.Type, arg, m, texts)
+		if err != nil {
+			return nil, fmt.Errorf("bad choices tag: %w", err)
+		}
+	}
+	return arg, nil
+}
+
+// The switch name for a field name, the reverse of argumentVariableName():
+// "NoVerify" becomes "no-verify", and "HTTPPort" becomes "http-port".
+func switchNameFromField(fieldName string) string {
+	var name []rune
+	runes := []rune(fieldName)
+	for i, r := range runes {
+		lower := strings.ToLower(string(r))
+		if i > 0 && string(r) != lower {
+			// A new word starts at an upper-case letter after a
+			// lower-case one, or at the last upper-case letter
+			// of an acronym, as in "HTTPPort".
+			prevLower := strings.ToLower(string(runes[i-1])) == string(runes[i-1])
+			nextLower := i+1 < len(runes) &&
+				strings.ToLower(string(runes[i+1])) == string(runes[i+1])
+			if prevLower || nextLower {
+				name = append(name, '-')
+			}
+		}
+		name = append(name, []rune(lower)...)
+	}
+	return string(name)
+}
+
+// Parse the choices from a tag as values of the type of the field's
+// items (or map keys)
+func choicesFromStrings(fieldType reflect.Type, arg *Argument, m *Messages,
+	texts []string) (interface{}, error) {
+
+	fieldValue, err := newValueType(reflect.New(fieldType).Elem(), arg)
+	if err != nil {
+		return nil, err
+	}
+	itemType := fieldType
+	switch v := fieldValue.(type) {
+	case *mapValueT:
+		itemType = v.keyScratch.Type()
+	case *pointerValueT:
+		itemType = v.elemType
+	default:
+		if fieldValue.storageType() == Slice {
+			itemType = fieldType.Elem()
+		}
+	}
+
+	choices := reflect.MakeSlice(reflect.SliceOf(itemType), 0, len(texts))
+	for _, text := range texts {
+		item := reflect.New(itemType).Elem()
+		itemValue, err := newValueType(item, arg)
+		if err != nil {
+			return nil, err
+		}
+		err = itemValue.parse(m, text)
+		if err != nil {
+			return nil, err
+		}
+		choices = reflect.Append(choices, item)
+	}
+	return choices.Interface(), nil
+}
+
+// Add Arguments to the root command from the tags of its Values struct
+func (self *ArgumentParser) AddFromStruct() {
+	self.Root.AddFromStruct()
+}
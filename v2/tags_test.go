@@ -0,0 +1,133 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type TagTestOptions struct {
+	Verbose  bool     `argparse:"-v,--verbose" help:"Be chatty"`
+	DryRun   bool     `argparse:"" help:"Don't do anything"`
+	HTTPPort int      `argparse:"" choices:"80, 8080"`
+	Color    bool     `argparse:"--color" negatable:"true"`
+	Level    int      `argparse:"--level" metavar:"N" required:"true"`
+	Format   string   `argparse:"--format" choices:"json,text"`
+	Pair     []string `argparse:"--pair" nargs:"2"`
+	Ignored  string
+	Skipped  string   `argparse:"-"`
+	Input    string   `argparse:"input"`
+	Names    []string `argparse:"names" nargs:"*" help:"Some names"`
+}
+
+func createTagTestParser() (*TagTestOptions, *ArgumentParser) {
+	opts := &TagTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.AddFromStruct()
+	return opts, ap
+}
+
+func (s *MySuite) TestTagsParse(c *C) {
+	opts, ap := createTagTestParser()
+
+	argv := []string{"-v", "--dry-run", "--http-port", "8080", "--no-color",
+		"--level", "3", "--format", "text", "--pair", "a", "b", "in", "x", "y"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbose, Equals, true)
+	c.Check(opts.DryRun, Equals, true)
+	c.Check(opts.HTTPPort, Equals, 8080)
+	c.Check(opts.Color, Equals, false)
+	c.Check(opts.Level, Equals, 3)
+	c.Check(opts.Format, Equals, "text")
+	c.Check(opts.Pair, DeepEquals, []string{"a", "b"})
+	c.Check(opts.Input, Equals, "in")
+	c.Check(opts.Names, DeepEquals, []string{"x", "y"})
+}
+
+type TagTestRootOptions struct {
+	Verbose bool `argparse:"-v,--verbose" inherit:"true"`
+}
+
+type TagTestSubOptions struct {
+	Verbose bool
+	Force   bool `argparse:"-f,--force"`
+}
+
+func (s *MySuite) TestTagsSubCommand(c *C) {
+	opts := &TagTestRootOptions{}
+	subOpts := &TagTestSubOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.AddFromStruct()
+	sub := ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+	sub.AddFromStruct()
+
+	argv := []string{"sub", "-f", "-v"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(subOpts.Force, Equals, true)
+	c.Check(subOpts.Verbose, Equals, true)
+}
+
+func (s *MySuite) TestTagsErrors(c *C) {
+	_, ap := createTagTestParser()
+	results := ap.parseArgv([]string{"in"})
+	c.Check(results.parseError, ErrorMatches, "Missing required switch: --level")
+
+	_, ap = createTagTestParser()
+	results = ap.parseArgv([]string{"--level", "1", "--http-port", "81", "in"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --http-port: Not a valid choice. Should be one of: \[80 8080\]`)
+
+	_, ap = createTagTestParser()
+	results = ap.parseArgv([]string{"--level", "1", "--ignored", "x", "in"})
+	c.Check(results.parseError, ErrorMatches, "No such switch: --ignored")
+}
+
+func (s *MySuite) TestTagsHelp(c *C) {
+	_, ap := createTagTestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*-v,--verbose +Be chatty\n.*`)
+	c.Check(help, Matches, `(?s).*--level=N +\(required\)\n.*`)
+	c.Check(help, Matches, `(?s).*--\[no-\]color *\n.*`)
+	c.Check(help, Matches, `(?s).*names\[ \.\.\. \] \] +Some names\n.*`)
+}
+
+func (s *MySuite) TestTagsBad(c *C) {
+	type BadNargs struct {
+		Names []string `argparse:"--names" nargs:"lots"`
+	}
+	type BadBool struct {
+		Force bool `argparse:"--force" required:"yes please"`
+	}
+	type BadChoices struct {
+		Port int `argparse:"--port" choices:"80,http"`
+	}
+
+	c.Check(func() {
+		New(&Command{Values: &BadNargs{}}).AddFromStruct()
+	}, PanicMatches, `Field Names of argparse.BadNargs: bad nargs tag "lots"`)
+	c.Check(func() {
+		New(&Command{Values: &BadBool{}}).AddFromStruct()
+	}, PanicMatches, `Field Force of argparse.BadBool: bad required tag "yes please"`)
+	c.Check(func() {
+		New(&Command{Values: &BadChoices{}}).AddFromStruct()
+	}, PanicMatches, `Field Port of argparse.BadChoices: bad choices tag: Cannot convert "http" to an integer.*`)
+}
+
+func (s *MySuite) TestSwitchNameFromField(c *C) {
+	c.Check(switchNameFromField("NoVerify"), Equals, "no-verify")
+	c.Check(switchNameFromField("HTTPPort"), Equals, "http-port")
+	c.Check(switchNameFromField("N"), Equals, "n")
+	c.Check(switchNameFromField("MaxIdleConns"), Equals, "max-idle-conns")
+}
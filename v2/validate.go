@@ -0,0 +1,213 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements the validation of values after they are parsed:
+// Min, Max, Pattern, and Validate.
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+)
+
+// Check the Min, Max, and Pattern fields against the destination type,
+// and compile the Pattern.
+func (self *Argument) sanityCheckValidators() error {
+	itemType := self.itemType()
+
+	for _, bound := range []interface{}{self.Min, self.Max} {
+		if bound == nil {
+			continue
+		}
+		if !isNumericKind(itemType.Kind()) {
+			return fmt.Errorf("Argument %s: Min and Max need a numeric destination",
+				self.PrettyName())
+		}
+		if _, ok := bigNumber(reflect.ValueOf(bound)); !ok {
+			return fmt.Errorf("Argument %s: Min and Max must be numbers, not %T",
+				self.PrettyName(), bound)
+		}
+	}
+	if self.Min != nil && self.Max != nil {
+		if cmp, _ := compareNumbers(reflect.ValueOf(self.Min), reflect.ValueOf(self.Max)); cmp > 0 {
+			return fmt.Errorf("Argument %s: Min is more than Max", self.PrettyName())
+		}
+	}
+
+	if self.Pattern != "" {
+		if itemType.Kind() != reflect.String {
+			return fmt.Errorf("Argument %s: Pattern needs a string destination",
+				self.PrettyName())
+		}
+		var err error
+		self.patternRegexp, err = regexp.Compile("^(?:" + self.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("Argument %s: bad Pattern: %w", self.PrettyName(), err)
+		}
+	}
+	return nil
+}
+
+// The type of each value given for this argument: the type of the
+// destination, or the item type of a slice, or the type a pointer
+// points to.
+func (self *Argument) itemType() reflect.Type {
+	itemType := self.value.getValue().Type()
+	if self.value.storageType() == Slice && itemType.Kind() == reflect.Slice {
+		return itemType.Elem()
+	}
+	if _, ok := self.value.(*pointerValueT); ok {
+		return itemType.Elem()
+	}
+	return itemType
+}
+
+// Does the argument have any checks for its values?
+func (self *Argument) hasValidators() bool {
+	return self.Min != nil || self.Max != nil || self.patternRegexp != nil || self.Validate != nil
+}
+
+// Check the value that parsing the text would store, without storing it
+func (self *Argument) checkValue(m *Messages, text string) error {
+	if !self.hasValidators() {
+		return nil
+	}
+	scratch := self.scratchValue(m)
+	err := scratch.parse(m, text)
+	if err != nil {
+		return err
+	}
+	return self.validate(m, scratch)
+}
+
+// Check the value that seeing the argument without a value would store,
+// like the next count of an ActionCount, without storing it
+func (self *Argument) checkWithoutValue(m *Messages) error {
+	if !self.hasValidators() {
+		return nil
+	}
+	scratch := self.scratchValue(m)
+	err := scratch.seenWithoutValue(m)
+	if err != nil {
+		return err
+	}
+	return self.validate(m, scratch)
+}
+
+// A value type for a copy of the destination, with the same choices and
+// map settings, to store a value in before it is checked. The copy is
+// shallow, so a flag.Value that keeps its values in a map, or behind a
+// pointer, changes the destination when it is Set.
+func (self *Argument) scratchValue(m *Messages) valueType {
+	destination := self.value.getValue()
+	scratchField := reflect.New(destination.Type()).Elem()
+	scratchField.Set(destination)
+
+	scratch, err := self.newValue(scratchField)
+	if err != nil {
+		panic(fmt.Sprintf("not reached for arg %s: %s", self.PrettyName(), err))
+	}
+	// A new map value copies the map before changing it, so the
+	// destination's map is not changed
+	if mapValue, ok := self.value.(*mapValueT); ok {
+		scratchMap := scratch.(*mapValueT)
+		scratchMap.separator = mapValue.separator
+		scratchMap.duplicates = mapValue.duplicates
+	}
+	if self.choiceValues != nil {
+		err = scratch.setChoices(m, self.choiceValues)
+		if err != nil {
+			panic(fmt.Sprintf("not reached for arg %s: %s", self.PrettyName(), err))
+		}
+	}
+	return scratch
+}
+
+// Check the value that was stored in the value type
+func (self *Argument) validate(m *Messages, valueType valueType) error {
+	if !self.hasValidators() {
+		return nil
+	}
+	value := lastValueOf(valueType)
+
+	item := reflect.ValueOf(value)
+	if _, ok := valueType.(*pointerValueT); ok {
+		item = item.Elem()
+	}
+	// A NaN is outside of every range
+	if self.Min != nil {
+		if cmp, ok := compareNumbers(item, reflect.ValueOf(self.Min)); !ok || cmp < 0 {
+			return fmt.Errorf(m.LessThanMinFmt, item.Interface(), self.Min)
+		}
+	}
+	if self.Max != nil {
+		if cmp, ok := compareNumbers(item, reflect.ValueOf(self.Max)); !ok || cmp > 0 {
+			return fmt.Errorf(m.MoreThanMaxFmt, item.Interface(), self.Max)
+		}
+	}
+	if self.patternRegexp != nil && !self.patternRegexp.MatchString(item.String()) {
+		return fmt.Errorf(m.DoesNotMatchPatternFmt, item.String(), self.Pattern)
+	}
+
+	if self.Validate != nil {
+		return self.Validate(value)
+	}
+	return nil
+}
+
+// The constraints on the value, for the help text
+func (self *Argument) constraintHelp(m *Messages) string {
+	switch {
+	case self.Min != nil && self.Max != nil:
+		return fmt.Sprintf(m.BetweenHelpFmt, self.Min, self.Max)
+	case self.Min != nil:
+		return fmt.Sprintf(m.MinHelpFmt, self.Min)
+	case self.Max != nil:
+		return fmt.Sprintf(m.MaxHelpFmt, self.Max)
+	case self.Pattern != "":
+		return fmt.Sprintf(m.PatternHelpFmt, self.Pattern)
+	}
+	return ""
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Any integer or float, as a big.Float, so that numbers of different
+// types can be compared without losing precision
+func bigNumber(value reflect.Value) (*big.Float, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		return new(big.Float).SetUint64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(value.Float()) {
+			return nil, false
+		}
+		return big.NewFloat(value.Float()), true
+	}
+	return nil, false
+}
+
+// Compare two numbers, returning -1, 0, or 1, as big.Float.Cmp does.
+// Returns false if either is not a number, including NaN.
+func compareNumbers(a, b reflect.Value) (int, bool) {
+	aNumber, aOk := bigNumber(a)
+	bNumber, bOk := bigNumber(b)
+	if !aOk || !bOk {
+		return 0, false
+	}
+	return aNumber.Cmp(bNumber), true
+}
@@ -0,0 +1,214 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type VTestOptions struct {
+	Port    uint16
+	Retries *int
+	Ratio   float64
+	Timeout time.Duration
+	Size    ByteSize
+	Name    string
+	Tags    []string
+	Even    int
+	Verbose int
+}
+
+func createVTestParser() (*VTestOptions, *ArgumentParser) {
+	opts := &VTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--port"},
+		Min:      1024,
+		Max:      65535,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--retries"},
+		Min:      0,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--ratio"},
+		Min:      0.0,
+		Max:      1,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--timeout"},
+		Max:      time.Minute,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--size"},
+		Max:      MiB,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--name"},
+		Pattern:  "[a-z][a-z0-9-]*",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--tags"},
+		Pattern:  "[a-z]+",
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--even"},
+		Validate: func(value interface{}) error {
+			if value.(int)%2 != 0 {
+				return errors.New("must be even")
+			}
+			return nil
+		},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"-v"},
+		Dest:     "Verbose",
+		Action:   ActionCount,
+		Max:      2,
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestValidateGood(c *C) {
+	opts, ap := createVTestParser()
+
+	argv := []string{"--port", "1024", "--retries", "0", "--ratio", "1.0",
+		"--timeout", "1m", "--size", "1MiB", "--name", "web-1", "--tags", "a",
+		"--tags", "b", "--even", "4"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Port, Equals, uint16(1024))
+	c.Check(*opts.Retries, Equals, 0)
+	c.Check(opts.Ratio, Equals, 1.0)
+	c.Check(opts.Timeout, Equals, time.Minute)
+	c.Check(opts.Size, Equals, MiB)
+	c.Check(opts.Name, Equals, "web-1")
+	c.Check(opts.Tags, DeepEquals, []string{"a", "b"})
+	c.Check(opts.Even, Equals, 4)
+}
+
+func (s *MySuite) TestValidateBad(c *C) {
+	tests := []struct {
+		argv     []string
+		expected string
+	}{
+		{[]string{"--port", "80"},
+			"While parsing value for --port: 80 is less than the minimum of 1024"},
+		{[]string{"--retries=-1"},
+			"While parsing value for --retries: -1 is less than the minimum of 0"},
+		{[]string{"--ratio", "1.5"},
+			"While parsing value for --ratio: 1.5 is more than the maximum of 1"},
+		{[]string{"--ratio", "NaN"},
+			"While parsing value for --ratio: NaN is less than the minimum of 0"},
+		{[]string{"--timeout", "1h"},
+			"While parsing value for --timeout: 1h0m0s is more than the maximum of 1m0s"},
+		{[]string{"--size", "2M"},
+			"While parsing value for --size: 2MB is more than the maximum of 1MiB"},
+		{[]string{"--name", "Web"},
+			`While parsing value for --name: "Web" does not match the pattern \[a-z\]\[a-z0-9-\]\*`},
+		{[]string{"--tags", "a", "--tags", "b2"},
+			`While parsing value for --tags: "b2" does not match the pattern \[a-z\]\+`},
+		{[]string{"--even", "3"},
+			"While parsing value for --even: must be even"},
+		{[]string{"-vvvv"},
+			"While parsing value for -v: 3 is more than the maximum of 2"},
+	}
+
+	for _, test := range tests {
+		_, ap := createVTestParser()
+		results := ap.parseArgv(test.argv)
+		c.Check(results.parseError, ErrorMatches, test.expected)
+	}
+}
+
+func (s *MySuite) TestValidateCount(c *C) {
+	opts, ap := createVTestParser()
+
+	results := ap.parseArgv([]string{"-vv"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Verbose, Equals, 2)
+}
+
+// A value that is rejected is not stored
+func (s *MySuite) TestValidateBadNotStored(c *C) {
+	opts, ap := createVTestParser()
+	opts.Port = 8080
+
+	results := ap.parseArgv([]string{"--port", "80"})
+	c.Check(results.parseError, NotNil)
+	c.Check(opts.Port, Equals, uint16(8080))
+
+	opts, ap = createVTestParser()
+	results = ap.parseArgv([]string{"--retries=-1"})
+	c.Check(results.parseError, NotNil)
+	c.Check(opts.Retries, IsNil)
+
+	opts, ap = createVTestParser()
+	results = ap.parseArgv([]string{"--tags", "a", "--tags", "b2"})
+	c.Check(results.parseError, NotNil)
+	c.Check(opts.Tags, DeepEquals, []string{"a"})
+
+	opts, ap = createVTestParser()
+	results = ap.parseArgv([]string{"-vvv"})
+	c.Check(results.parseError, NotNil)
+	c.Check(opts.Verbose, Equals, 2)
+}
+
+func (s *MySuite) TestValidateHelp(c *C) {
+	_, ap := createVTestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--port=PORT +\(from 1024 to 65535\)\n.*`)
+	c.Check(help, Matches, `(?s).*--retries=RETRIES +\(at least 0\)\n.*`)
+	c.Check(help, Matches, `(?s).*--timeout=TIMEOUT +\(a duration, like 90s, 1h30m, or 7d\) \(at most\s+1m0s\)\n.*`)
+	c.Check(help, Matches, `(?s).*--name=NAME +\(matching \[a-z\]\[a-z0-9-\]\*\)\n.*`)
+	c.Check(help, Matches, `(?s).*--even=EVEN +\n.*`)
+}
+
+func (s *MySuite) TestValidateBadDefinitions(c *C) {
+	_, ap := createVTestParser()
+
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--name2"},
+			Dest:     "Name",
+			Min:      1,
+		})
+	}, PanicMatches, "Argument --name2: Min and Max need a numeric destination")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--port2"},
+			Dest:     "Port",
+			Max:      "big",
+		})
+	}, PanicMatches, "Argument --port2: Min and Max must be numbers, not string")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--port2"},
+			Dest:     "Port",
+			Min:      10,
+			Max:      math.Inf(-1),
+		})
+	}, PanicMatches, "Argument --port2: Min is more than Max")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--port2"},
+			Dest:     "Port",
+			Pattern:  "[0-9]+",
+		})
+	}, PanicMatches, "Argument --port2: Pattern needs a string destination")
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--name2"},
+			Dest:     "Name",
+			Pattern:  "[a-z",
+		})
+	}, PanicMatches, "Argument --name2: bad Pattern: .*")
+}
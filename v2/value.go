@@ -7,8 +7,11 @@ package argparse
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,8 +20,14 @@ type valueStorageType int
 const (
 	Scalar valueStorageType = iota
 	Slice
+	Map
 )
 
+// Can the destination hold more than one value?
+func (self valueStorageType) holdsMany() bool {
+	return self == Slice || self == Map
+}
+
 type valueType interface {
 
 	// Parse the text into the destination value
@@ -26,7 +35,7 @@ type valueType interface {
 
 	// If the switch is seen but has no value after it.
 	// This is only legal for bools
-	seenWithoutValue() error
+	seenWithoutValue(m *Messages) error
 
 	defaultSwitchNumArgs() int
 
@@ -38,6 +47,86 @@ type valueType interface {
 	storageType() valueStorageType
 }
 
+// Create the valueType for a destination field (or any other settable
+// reflect.Value), based on its type. Some types use options from the
+// Argument.
+func newValueType(valueP reflect.Value, arg *Argument) (valueType, error) {
+	fieldType := valueP.Type()
+
+	switch fieldType.String() {
+	case "time.Duration":
+		return newDurationValueT(valueP), nil
+	}
+
+	if parseItem, ok := builtinItemParser(fieldType, arg); ok {
+		return newFuncValueT(valueP, fieldType, parseItem, Scalar, 1), nil
+	}
+
+	// Types that know how to parse themselves
+	if parseItem, numArgs, ok := selfParsingItemParser(valueP); ok {
+		return newFuncValueT(valueP, fieldType, parseItem, Scalar, numArgs), nil
+	}
+
+	// We may want to look at fieldType.String() for all types here,
+	// since we really do want the dynamic type not the concrete type
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		return newBoolValueT(valueP), nil
+	case reflect.String:
+		return newStringValueT(valueP), nil
+	case reflect.Int64:
+		return newInt64ValueT(valueP), nil
+	case reflect.Int:
+		return newIntValueT(valueP), nil
+	case reflect.Float64:
+		return newFloatValueT(valueP), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32:
+		return newFuncValueT(valueP, fieldType, numericItemParser(fieldType), Scalar, 1), nil
+	case reflect.Ptr:
+		return newPointerValueT(valueP, arg)
+	case reflect.Map:
+		return newMapValueT(valueP, arg)
+	case reflect.Slice:
+		sliceType := fieldType.Elem()
+		switch sliceType.String() {
+		case "time.Duration":
+			return newDurationSliceValueT(valueP), nil
+		}
+
+		if parseItem, ok := builtinItemParser(sliceType, arg); ok {
+			return newFuncValueT(valueP, sliceType, parseItem, Slice, 1), nil
+		}
+
+		if parseItem, ok := selfParsingSliceItemParser(sliceType); ok {
+			return newFuncValueT(valueP, sliceType, parseItem, Slice, 1), nil
+		}
+
+		sliceKind := sliceType.Kind()
+		switch sliceKind {
+		case reflect.Bool:
+			return newBoolSliceValueT(valueP), nil
+		case reflect.Int64:
+			return newInt64SliceValueT(valueP), nil
+		case reflect.Int:
+			return newIntSliceValueT(valueP), nil
+		case reflect.String:
+			return newStringSliceValueT(valueP), nil
+		case reflect.Float64:
+			return newFloatSliceValueT(valueP), nil
+		case reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Uintptr, reflect.Float32:
+			return newFuncValueT(valueP, sliceType, numericItemParser(sliceType), Slice, 1), nil
+		default:
+			return nil, fmt.Errorf("cannot be of type []%s", sliceKind.String())
+		}
+	default:
+		return nil, fmt.Errorf("cannot be of type %s", fieldType.String())
+	}
+}
+
 type valueT struct {
 	// A "pointer" to where to store the parsed value
 	value reflect.Value
@@ -66,7 +155,7 @@ func (self *boolValueT) defaultSwitchNumArgs() int {
 	return 0
 }
 
-func (self *boolValueT) seenWithoutValue() error {
+func (self *boolValueT) seenWithoutValue(m *Messages) error {
 	self.value.SetBool(true)
 	return nil
 }
@@ -121,7 +210,7 @@ func (self *stringValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *stringValueT) seenWithoutValue() error {
+func (self *stringValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a string value")
 }
 
@@ -170,31 +259,42 @@ func (self *intValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *intValueT) seenWithoutValue() error {
+func (self *intValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need an int value")
 }
 
 func text_to_int64(text string) (int64, error) {
-	// hex?
+	return text_to_int(text, 64)
+}
+
+// Split an integer string into its digits and its base. Hex numbers
+// start with "0x", and octal numbers start with "0o" or just "0".
+func integer_base(text string) (string, int) {
 	if len(text) > 2 && text[0:2] == "0x" {
-		text = text[2:]
-		return strconv.ParseInt(text, 16, 64)
+		return text[2:], 16
 	} else if len(text) > 2 && text[0:2] == "0o" {
-		// octal with "0o"?
-		text = text[2:]
-		return strconv.ParseInt(text, 8, 64)
+		return text[2:], 8
 	} else if len(text) > 1 && text[0:1] == "0" {
-		// octal with "0"?
-		text = text[1:]
-		return strconv.ParseInt(text, 8, 64)
+		return text[1:], 8
 	} else {
-		// decimal
-		return strconv.ParseInt(text, 10, 64)
+		return text, 10
 	}
 }
 
+// Convert the text to a signed integer that fits in bitSize bits
+func text_to_int(text string, bitSize int) (int64, error) {
+	digits, base := integer_base(text)
+	return strconv.ParseInt(digits, base, bitSize)
+}
+
+// Convert the text to an unsigned integer that fits in bitSize bits
+func text_to_uint(text string, bitSize int) (uint64, error) {
+	digits, base := integer_base(text)
+	return strconv.ParseUint(digits, base, bitSize)
+}
+
 func (self *intValueT) parse(m *Messages, text string) error {
-	i64, err := text_to_int64(text)
+	i64, err := text_to_int(text, strconv.IntSize)
 	i := int(i64)
 	if err != nil {
 		return fmt.Errorf("Cannot convert \"%s\" to an integer: %w", text, err)
@@ -243,7 +343,7 @@ func (self *int64ValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *int64ValueT) seenWithoutValue() error {
+func (self *int64ValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need an int64 value")
 }
 
@@ -296,7 +396,7 @@ func (self *floatValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *floatValueT) seenWithoutValue() error {
+func (self *floatValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a float value")
 }
 
@@ -336,6 +436,55 @@ func (self *floatValueT) storageType() valueStorageType {
 
 // =========================================================== time.Duration
 
+var durationPartRegex = regexp.MustCompile(`([0-9.]+)([^0-9.]*)`)
+
+// Parse a duration as time.ParseDuration does, but also accept days ("d")
+// and weeks ("w"), as in "7d", "2w", or "1d12h". A day is always 24 hours.
+func parseDuration(text string) (time.Duration, error) {
+	d, err := time.ParseDuration(text)
+	if err == nil || !strings.ContainsAny(text, "dw") {
+		return d, err
+	}
+
+	body := strings.TrimLeft(text, "+-")
+	if body == "" || durationPartRegex.ReplaceAllString(body, "") != "" {
+		return 0, err
+	}
+	var total time.Duration
+	for _, part := range durationPartRegex.FindAllStringSubmatch(body, -1) {
+		var hours float64
+		switch part[2] {
+		case "d":
+			hours = 24
+		case "w":
+			hours = 7 * 24
+		default:
+			partDuration, err := time.ParseDuration(part[0])
+			if err != nil {
+				return 0, err
+			}
+			if partDuration > math.MaxInt64-total {
+				return 0, fmt.Errorf("duration \"%s\" is too long", text)
+			}
+			total += partDuration
+			continue
+		}
+		count, err := strconv.ParseFloat(part[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number \"%s\" in duration", part[1])
+		}
+		nanoseconds := count * hours * float64(time.Hour)
+		if nanoseconds >= math.MaxInt64-float64(total) {
+			return 0, fmt.Errorf("duration \"%s\" is too long", text)
+		}
+		total += time.Duration(nanoseconds)
+	}
+	if strings.HasPrefix(text, "-") {
+		total = -total
+	}
+	return total, nil
+}
+
 type durationValueT struct {
 	valueT
 	choices []time.Duration
@@ -349,13 +498,13 @@ func (self *durationValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *durationValueT) seenWithoutValue() error {
+func (self *durationValueT) seenWithoutValue(m *Messages) error {
 	// TODO - needs to support i18n
 	return errors.New("Need a time duration string")
 }
 
 func (self *durationValueT) parse(m *Messages, text string) error {
-	d, err := time.ParseDuration(text)
+	d, err := parseDuration(text)
 	if err != nil {
 		return fmt.Errorf("Cannot parse \"%s\" as a time duration: %s", text, err)
 	}
@@ -403,7 +552,7 @@ func (self *boolSliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *boolSliceValueT) seenWithoutValue() error {
+func (self *boolSliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a bool value")
 }
 
@@ -458,7 +607,7 @@ func (self *stringSliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *stringSliceValueT) seenWithoutValue() error {
+func (self *stringSliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a string value")
 }
 
@@ -508,12 +657,12 @@ func (self *intSliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *intSliceValueT) seenWithoutValue() error {
+func (self *intSliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need an int value")
 }
 
 func (self *intSliceValueT) parse(m *Messages, text string) error {
-	i64, err := text_to_int64(text)
+	i64, err := text_to_int(text, strconv.IntSize)
 	if err != nil {
 		return fmt.Errorf("Cannot convert \"%s\" to an integer: %w", text, err)
 	}
@@ -563,7 +712,7 @@ func (self *int64SliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *int64SliceValueT) seenWithoutValue() error {
+func (self *int64SliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need an int64 value")
 }
 
@@ -617,7 +766,7 @@ func (self *floatSliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *floatSliceValueT) seenWithoutValue() error {
+func (self *floatSliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a float value")
 }
 
@@ -671,12 +820,12 @@ func (self *durationSliceValueT) defaultSwitchNumArgs() int {
 	return 1
 }
 
-func (self *durationSliceValueT) seenWithoutValue() error {
+func (self *durationSliceValueT) seenWithoutValue(m *Messages) error {
 	return errors.New("Need a time duration value")
 }
 
 func (self *durationSliceValueT) parse(m *Messages, text string) error {
-	d, err := time.ParseDuration(text)
+	d, err := parseDuration(text)
 	if err != nil {
 		return fmt.Errorf("Cannot parse \"%s\" as a time duration: %s", text, err)
 	}
@@ -0,0 +1,164 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements the types that argparse knows how to parse,
+// beyond the basic kinds: network addresses, URLs, file modes, regular
+// expressions, times, byte sizes, and enums.
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ipType       = reflect.TypeOf(net.IP{})
+	ipNetType    = reflect.TypeOf(net.IPNet{})
+	addrPortType = reflect.TypeOf(netip.AddrPort{})
+	urlType      = reflect.TypeOf(url.URL{})
+	urlPtrType   = reflect.TypeOf(&url.URL{})
+	fileModeType = reflect.TypeOf(os.FileMode(0))
+	regexpType   = reflect.TypeOf(&regexp.Regexp{})
+	timeType     = reflect.TypeOf(time.Time{})
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// If the item type is one of the built-in types, return the function
+// that parses text into a new item. Some types are also
+// TextUnmarshalers, but are parsed here to give better errors, or to
+// use the options in the Argument.
+func builtinItemParser(itemType reflect.Type, arg *Argument) (parseItemFunc, bool) {
+	if mapping, ok := arg.enumMapping(itemType); ok {
+		return func(m *Messages, text string) (reflect.Value, error) {
+			return arg.parseEnum(mapping, m, text)
+		}, true
+	}
+
+	switch itemType {
+	case ipType:
+		return parseIP, true
+	case ipNetType:
+		return parseIPNet, true
+	case addrPortType:
+		return parseAddrPort, true
+	case urlType, urlPtrType:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			item, err := url.Parse(text)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as a URL: %w",
+					text, unwrapURLError(err))
+			}
+			if itemType == urlType {
+				return reflect.ValueOf(*item), nil
+			}
+			return reflect.ValueOf(item), nil
+		}, true
+	case fileModeType:
+		return parseFileMode, true
+	case regexpType:
+		return parseRegexp, true
+	case byteSizeType:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			size, err := ParseByteSize(text, arg.StrictByteSize)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(size), nil
+		}, true
+	case timeType:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			return parseTime(arg.timeLayouts(), text)
+		}, true
+	}
+	return nil, false
+}
+
+func parseIP(m *Messages, text string) (reflect.Value, error) {
+	ip := net.ParseIP(text)
+	if ip == nil {
+		return reflect.Value{}, fmt.Errorf("\"%s\" is not a valid IP address", text)
+	}
+	return reflect.ValueOf(ip), nil
+}
+
+func parseIPNet(m *Messages, text string) (reflect.Value, error) {
+	_, ipNet, err := net.ParseCIDR(text)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("\"%s\" is not a valid CIDR network", text)
+	}
+	return reflect.ValueOf(*ipNet), nil
+}
+
+func parseAddrPort(m *Messages, text string) (reflect.Value, error) {
+	addrPort, err := netip.ParseAddrPort(text)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("\"%s\" is not a valid address and port", text)
+	}
+	return reflect.ValueOf(addrPort), nil
+}
+
+// The *url.Error repeats the text; we only need the reason
+func unwrapURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
+
+// Parse permissions in octal, as chmod does, including the setuid (4000),
+// setgid (2000), and sticky (1000) bits.
+func parseFileMode(m *Messages, text string) (reflect.Value, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(text, "0o"), "0O")
+	bits, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || bits > 07777 || digits == "" {
+		return reflect.Value{}, fmt.Errorf("\"%s\" is not an octal file mode", text)
+	}
+	mode := os.FileMode(bits) & os.ModePerm
+	if bits&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return reflect.ValueOf(mode), nil
+}
+
+func parseRegexp(m *Messages, text string) (reflect.Value, error) {
+	re, err := regexp.Compile(text)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("Cannot compile \"%s\" as a regular expression: %w",
+			text, err)
+	}
+	return reflect.ValueOf(re), nil
+}
+
+// Try each of the layouts in turn
+func parseTime(layouts []string, text string) (reflect.Value, error) {
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, text)
+		if err == nil {
+			return reflect.ValueOf(t), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as a time; expected a time like \"%s\"",
+		text, strings.Join(layouts, "\" or \""))
+}
+
+// The layouts for parsing a time.Time destination
+func (self *Argument) timeLayouts() []string {
+	if len(self.TimeLayouts) > 0 {
+		return self.TimeLayouts
+	}
+	return []string{time.RFC3339}
+}
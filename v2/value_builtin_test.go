@@ -0,0 +1,135 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type BTestOptions struct {
+	Addr     net.IP
+	Network  net.IPNet
+	Listen   netip.AddrPort
+	Endpoint *url.URL
+	Mode     os.FileMode
+	Match    *regexp.Regexp
+	Since    time.Time
+	Until    *time.Time
+	Day      time.Time
+	Peers    []net.IP
+	Mirrors  []*url.URL
+}
+
+func createBTestParser() (*BTestOptions, *ArgumentParser) {
+	opts := &BTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	for _, switchName := range []string{"--addr", "--network", "--listen",
+		"--endpoint", "--mode", "--match", "--since", "--until", "--peers",
+		"--mirrors"} {
+		ap.Add(&Argument{
+			Switches: []string{switchName},
+		})
+	}
+	ap.Add(&Argument{
+		Switches:    []string{"--day"},
+		TimeLayouts: []string{"2006-01-02", "01/02/2006"},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestBuiltinValues(c *C) {
+	opts, ap := createBTestParser()
+
+	argv := []string{"--addr", "10.1.2.3", "--network", "192.168.1.7/24",
+		"--listen", "[::1]:8080", "--endpoint", "https://example.com/api?x=1",
+		"--mode", "0755", "--match", "^web-[0-9]+$",
+		"--since", "2024-05-01T10:00:00Z", "--until", "2024-05-02T10:00:00+02:00",
+		"--day", "05/03/2024", "--peers", "::1", "--peers", "10.0.0.1",
+		"--mirrors", "http://a.example", "--mirrors", "http://b.example"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Addr.String(), Equals, "10.1.2.3")
+	c.Check(opts.Network.String(), Equals, "192.168.1.0/24")
+	c.Check(opts.Listen, Equals, netip.MustParseAddrPort("[::1]:8080"))
+	c.Assert(opts.Endpoint, NotNil)
+	c.Check(opts.Endpoint.Host, Equals, "example.com")
+	c.Check(opts.Endpoint.Path, Equals, "/api")
+	c.Check(opts.Mode, Equals, os.FileMode(0755))
+	c.Assert(opts.Match, NotNil)
+	c.Check(opts.Match.MatchString("web-12"), Equals, true)
+	c.Check(opts.Since, DeepEquals, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	c.Assert(opts.Until, NotNil)
+	c.Check(opts.Until.UTC(), DeepEquals, time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC))
+	c.Check(opts.Day, DeepEquals, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC))
+	c.Assert(opts.Peers, HasLen, 2)
+	c.Check(opts.Peers[0].String(), Equals, "::1")
+	c.Check(opts.Peers[1].String(), Equals, "10.0.0.1")
+	c.Assert(opts.Mirrors, HasLen, 2)
+	c.Check(opts.Mirrors[1].Host, Equals, "b.example")
+}
+
+func (s *MySuite) TestBuiltinFileMode(c *C) {
+	opts, ap := createBTestParser()
+
+	results := ap.parseArgv([]string{"--mode", "4750"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Mode, Equals, os.ModeSetuid|0750)
+
+	opts, ap = createBTestParser()
+	results = ap.parseArgv([]string{"--mode", "0o1777"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Mode, Equals, os.ModeSticky|0777)
+}
+
+func (s *MySuite) TestBuiltinErrors(c *C) {
+	tests := []struct {
+		argv     []string
+		expected string
+	}{
+		{[]string{"--addr", "10.1.2"},
+			`While parsing value for --addr: "10.1.2" is not a valid IP address`},
+		{[]string{"--network", "10.0.0.0"},
+			`While parsing value for --network: "10.0.0.0" is not a valid CIDR network`},
+		{[]string{"--listen", "localhost:80"},
+			`While parsing value for --listen: "localhost:80" is not a valid address and port`},
+		{[]string{"--endpoint", "http://[::1"},
+			`While parsing value for --endpoint: Cannot parse "http://\[::1" as a URL: .*`},
+		{[]string{"--mode", "0789"},
+			`While parsing value for --mode: "0789" is not an octal file mode`},
+		{[]string{"--mode", "17777"},
+			`While parsing value for --mode: "17777" is not an octal file mode`},
+		{[]string{"--match", "web-[0-9"},
+			`While parsing value for --match: Cannot compile "web-\[0-9" as a regular expression: .*missing closing \].*`},
+		{[]string{"--since", "yesterday"},
+			`While parsing value for --since: Cannot parse "yesterday" as a time; expected a time like "2006-01-02T15:04:05Z07:00"`},
+		{[]string{"--day", "May 3"},
+			`While parsing value for --day: Cannot parse "May 3" as a time; expected a time like "2006-01-02" or "01/02/2006"`},
+	}
+
+	for _, test := range tests {
+		_, ap := createBTestParser()
+		results := ap.parseArgv(test.argv)
+		c.Check(results.parseError, ErrorMatches, test.expected)
+	}
+}
+
+func (s *MySuite) TestBuiltinPointersNotSeen(c *C) {
+	opts, ap := createBTestParser()
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Endpoint, IsNil)
+	c.Check(opts.Match, IsNil)
+	c.Check(opts.Until, IsNil)
+}
@@ -0,0 +1,171 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements values for map destinations, which are given
+// as KEY=VALUE items.
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// What to do when a key is given more than once for a map destination
+type DuplicateKeyPolicy int
+
+const (
+	// The last value given for the key is stored
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+
+	// Giving the same key twice is an error
+	DuplicateKeyError
+)
+
+// The default text between the key and the value
+const defaultKeyValueSeparator = "="
+
+// Check the KeyValueSeparator and DuplicateKeys fields, and give them
+// to the map value.
+func (self *Argument) sanityCheckMap() error {
+	mapValue, isMap := self.value.(*mapValueT)
+	if !isMap {
+		if self.KeyValueSeparator != "" {
+			return fmt.Errorf("Argument %s: KeyValueSeparator needs a map destination",
+				self.PrettyName())
+		}
+		if self.DuplicateKeys != DuplicateKeyLastWins {
+			return fmt.Errorf("Argument %s: DuplicateKeys needs a map destination",
+				self.PrettyName())
+		}
+		return nil
+	}
+	switch self.DuplicateKeys {
+	case DuplicateKeyLastWins, DuplicateKeyError:
+	default:
+		return fmt.Errorf("Argument %s: unknown DuplicateKeys %d",
+			self.PrettyName(), self.DuplicateKeys)
+	}
+	if self.KeyValueSeparator != "" {
+		mapValue.separator = self.KeyValueSeparator
+	}
+	mapValue.duplicates = self.DuplicateKeys
+	return nil
+}
+
+// =========================================================== map
+
+// A map of scalar keys to scalar values. The key and the value of each
+// item are parsed into scratch values by the value types for their types.
+// Choices apply to the keys.
+type mapValueT struct {
+	valueT
+	key        valueType
+	keyScratch reflect.Value
+	elem       valueType
+	scratch    reflect.Value
+	separator  string
+	duplicates DuplicateKeyPolicy
+
+	// The keys given during this parse, to find duplicates. Until
+	// the first item is stored, the map is the caller's default,
+	// which is copied rather than modified.
+	given map[interface{}]bool
+}
+
+func newMapValueT(valueP reflect.Value, arg *Argument) (*mapValueT, error) {
+	fieldType := valueP.Type()
+
+	keyScratch := reflect.New(fieldType.Key()).Elem()
+	key, err := newValueType(keyScratch, arg)
+	if err != nil || key.storageType() != Scalar || key.defaultSwitchNumArgs() != 1 {
+		return nil, fmt.Errorf("cannot be of type %s", fieldType.String())
+	}
+	scratch := reflect.New(fieldType.Elem()).Elem()
+	elem, err := newValueType(scratch, arg)
+	if err != nil || elem.storageType() != Scalar {
+		return nil, fmt.Errorf("cannot be of type %s", fieldType.String())
+	}
+	return &mapValueT{
+		valueT:     valueT{valueP},
+		key:        key,
+		keyScratch: keyScratch,
+		elem:       elem,
+		scratch:    scratch,
+		separator:  defaultKeyValueSeparator,
+	}, nil
+}
+
+func (self *mapValueT) defaultSwitchNumArgs() int {
+	return 1
+}
+
+func (self *mapValueT) seenWithoutValue(m *Messages) error {
+	return fmt.Errorf("Need a KEY%sVALUE value", self.separator)
+}
+
+func (self *mapValueT) parse(m *Messages, text string) error {
+	i := strings.Index(text, self.separator)
+	if i == -1 {
+		return fmt.Errorf(m.MissingKeyValueSeparatorFmt, self.separator, text)
+	}
+	err := self.key.parse(m, text[:i])
+	if err != nil {
+		return err
+	}
+	err = self.elem.parse(m, text[i+len(self.separator):])
+	if err != nil {
+		return err
+	}
+
+	if self.given == nil {
+		self.given = make(map[interface{}]bool)
+		self.copyMap(self.value)
+	}
+	keyIntf := self.keyScratch.Interface()
+	if self.given[keyIntf] && self.duplicates == DuplicateKeyError {
+		return fmt.Errorf(m.DuplicateKeyFmt, text[:i])
+	}
+	self.given[keyIntf] = true
+	self.value.SetMapIndex(self.keyScratch, self.scratch)
+	return nil
+}
+
+// Point the destination at a new map with the items of the given map
+func (self *mapValueT) copyMap(valueP reflect.Value) {
+	newMap := reflect.MakeMap(self.value.Type())
+	if !valueP.IsNil() {
+		iter := valueP.MapRange()
+		for iter.Next() {
+			newMap.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	self.value.Set(newMap)
+}
+
+// Copy the map, so that a sub-command inheriting the value does not
+// share storage with its parent.
+func (self *mapValueT) setValue(valueP reflect.Value) {
+	if valueP.IsNil() {
+		self.value.Set(reflect.Zero(self.value.Type()))
+		return
+	}
+	self.copyMap(valueP)
+	self.given = make(map[interface{}]bool)
+	for _, key := range valueP.MapKeys() {
+		self.given[key.Interface()] = true
+	}
+}
+
+func (self *mapValueT) setChoices(m *Messages, choicesIntf interface{}) error {
+	return self.key.setChoices(m, choicesIntf)
+}
+
+func (self *mapValueT) storageType() valueStorageType {
+	return Map
+}
+
+// The default metavar for the help, like KEY=VALUE
+func (self *mapValueT) metaVar() string {
+	return "KEY" + self.separator + "VALUE"
+}
@@ -0,0 +1,179 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type MapTestOptions struct {
+	Label  map[string]string
+	Limit  map[string]int
+	Define map[string]string
+	Env    map[string]string
+}
+
+func createMapTestParser() (*MapTestOptions, *ArgumentParser) {
+	opts := &MapTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--label"},
+		Help:     "A label for the job",
+		Choices:  []string{"env", "team"},
+	})
+	ap.Add(&Argument{
+		Switches:      []string{"--limit"},
+		DuplicateKeys: DuplicateKeyError,
+	})
+	ap.Add(&Argument{
+		Switches:          []string{"-D", "--define"},
+		KeyValueSeparator: ":",
+	})
+	ap.Add(&Argument{
+		Name:        "env",
+		NumArgsGlob: "*",
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestMapValues(c *C) {
+	opts, ap := createMapTestParser()
+
+	argv := []string{"--label", "env=prod", "--label=team=infra",
+		"--limit", "cpu=2", "--limit", "mem=512", "-D", "a:b=c", "X=1", "Y=2"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Label, DeepEquals, map[string]string{"env": "prod", "team": "infra"})
+	c.Check(opts.Limit, DeepEquals, map[string]int{"cpu": 2, "mem": 512})
+	c.Check(opts.Define, DeepEquals, map[string]string{"a": "b=c"})
+	c.Check(opts.Env, DeepEquals, map[string]string{"X": "1", "Y": "2"})
+}
+
+func (s *MySuite) TestMapLastWins(c *C) {
+	opts, ap := createMapTestParser()
+
+	argv := []string{"--label", "env=prod", "--label", "env=dev"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Label, DeepEquals, map[string]string{"env": "dev"})
+}
+
+func (s *MySuite) TestMapDuplicateKeyError(c *C) {
+	_, ap := createMapTestParser()
+
+	argv := []string{"--limit", "cpu=2", "--limit", "cpu=4"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --limit: The key "cpu" was already given`)
+}
+
+func (s *MySuite) TestMapDefaultNotModified(c *C) {
+	opts, ap := createMapTestParser()
+	defaults := map[string]int{"cpu": 1, "mem": 256}
+	opts.Limit = defaults
+
+	// A default key can be given once, even with DuplicateKeyError
+	argv := []string{"--limit", "cpu=2"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Limit, DeepEquals, map[string]int{"cpu": 2, "mem": 256})
+	c.Check(defaults, DeepEquals, map[string]int{"cpu": 1, "mem": 256})
+}
+
+// Each parse starts over, with the map in the Values as the default
+func (s *MySuite) TestMapParseTwice(c *C) {
+	opts, ap := createMapTestParser()
+	opts.Limit = map[string]int{"mem": 256}
+
+	results := ap.parseArgv([]string{"--limit", "cpu=2"})
+	c.Assert(results.parseError, IsNil)
+	firstLimit := opts.Limit
+	c.Check(firstLimit, DeepEquals, map[string]int{"cpu": 2, "mem": 256})
+
+	results = ap.parseArgv([]string{"--limit", "cpu=4"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Limit, DeepEquals, map[string]int{"cpu": 4, "mem": 256})
+	c.Check(firstLimit, DeepEquals, map[string]int{"cpu": 2, "mem": 256})
+}
+
+func (s *MySuite) TestMapErrors(c *C) {
+	_, ap := createMapTestParser()
+	results := ap.parseArgv([]string{"--label", "env"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --label: Expected KEY=VALUE but got "env"`)
+
+	_, ap = createMapTestParser()
+	results = ap.parseArgv([]string{"--label", "owner=me"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --label: Not a valid choice. Should be one of: \[env team\]`)
+
+	_, ap = createMapTestParser()
+	results = ap.parseArgv([]string{"--limit", "cpu=lots"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --limit: Cannot convert "lots" to an integer.*`)
+
+	_, ap = createMapTestParser()
+	results = ap.parseArgv([]string{"-D", "a=b"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for -D: Expected KEY:VALUE but got "a=b"`)
+}
+
+func (s *MySuite) TestMapHelp(c *C) {
+	_, ap := createMapTestParser()
+
+	help := ap.helpString(ap.Root, nil)
+	c.Check(help, Matches, `(?s).*--label KEY=VALUE +A label for the job.*`)
+	c.Check(help, Matches, `(?s).*-D,--define KEY:VALUE.*`)
+}
+
+func (s *MySuite) TestMapBadDefinitions(c *C) {
+	type BadOptions struct {
+		Nested map[string][]string
+		Name   string
+	}
+	ap := New(&Command{
+		Values: &BadOptions{},
+	})
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--nested"},
+		})
+	}, PanicMatches, `Argument --nested cannot be of type map\[string\]\[\]string`)
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches:          []string{"--name"},
+			KeyValueSeparator: ":",
+		})
+	}, PanicMatches, `Argument --name: KeyValueSeparator needs a map destination`)
+}
+
+func (s *MySuite) TestMapInherited(c *C) {
+	opts := &MapTestOptions{}
+	subOpts := &MapTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--label"},
+		Inherit:  true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"--label", "env=prod", "sub"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(subOpts.Label, DeepEquals, map[string]string{"env": "prod"})
+
+	// The sub-command has its own copy
+	subOpts.Label["team"] = "infra"
+	c.Check(opts.Label, DeepEquals, map[string]string{"env": "prod"})
+}
@@ -0,0 +1,64 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements the integer and float types that don't have
+// their own value types; each is range-checked against its size.
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Return the function that parses text into a new item of a numeric type
+func numericItemParser(itemType reflect.Type) parseItemFunc {
+	bitSize := itemType.Bits()
+
+	switch itemType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			i, err := text_to_int(text, bitSize)
+			if err != nil {
+				return reflect.Value{}, numericParseError(text, itemType, err)
+			}
+			item := reflect.New(itemType).Elem()
+			item.SetInt(i)
+			return item, nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			u, err := text_to_uint(text, bitSize)
+			if err != nil {
+				return reflect.Value{}, numericParseError(text, itemType, err)
+			}
+			item := reflect.New(itemType).Elem()
+			item.SetUint(u)
+			return item, nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		return func(m *Messages, text string) (reflect.Value, error) {
+			f, err := strconv.ParseFloat(text, bitSize)
+			if err != nil {
+				return reflect.Value{}, numericParseError(text, itemType, err)
+			}
+			item := reflect.New(itemType).Elem()
+			item.SetFloat(f)
+			return item, nil
+		}
+
+	default:
+		panic(fmt.Sprintf("%s is not a numeric type", itemType.String()))
+	}
+}
+
+func numericParseError(text string, itemType reflect.Type, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%s is out of range for %s", text, itemType.String())
+	}
+	return fmt.Errorf("Cannot convert \"%s\" to %s", text, itemType.String())
+}
@@ -0,0 +1,130 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type NumTestOptions struct {
+	Int8    int8
+	Int16   int16
+	Int32   int32
+	Uint    uint
+	Uint8   uint8
+	Port    uint16
+	Uint32  uint32
+	Uint64  uint64
+	Ratio   float32
+	Ports   []uint16
+	Ratios  []float32
+	Offsets []int32
+}
+
+func createNumTestParser() (*NumTestOptions, *ArgumentParser) {
+	opts := &NumTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	for _, switchName := range []string{"--int8", "--int16", "--int32", "--uint",
+		"--uint8", "--port", "--uint32", "--uint64", "--ratio", "--ports",
+		"--ratios", "--offsets"} {
+		ap.Add(&Argument{
+			Switches: []string{switchName},
+		})
+	}
+	return opts, ap
+}
+
+func (s *MySuite) TestNumericScalars(c *C) {
+	opts, ap := createNumTestParser()
+
+	argv := []string{"--int8", "-128", "--int16", "0x7fff", "--int32", "-5",
+		"--uint", "7", "--uint8", "255", "--port", "8080", "--uint32", "0o17",
+		"--uint64", "18446744073709551615", "--ratio", "0.25"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Int8, Equals, int8(-128))
+	c.Check(opts.Int16, Equals, int16(0x7fff))
+	c.Check(opts.Int32, Equals, int32(-5))
+	c.Check(opts.Uint, Equals, uint(7))
+	c.Check(opts.Uint8, Equals, uint8(255))
+	c.Check(opts.Port, Equals, uint16(8080))
+	c.Check(opts.Uint32, Equals, uint32(15))
+	c.Check(opts.Uint64, Equals, uint64(18446744073709551615))
+	c.Check(opts.Ratio, Equals, float32(0.25))
+}
+
+func (s *MySuite) TestNumericSlices(c *C) {
+	opts, ap := createNumTestParser()
+
+	argv := []string{"--ports", "80", "--ports=443", "--ratios", "1.5",
+		"--offsets", "-1", "--offsets", "2"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Ports, DeepEquals, []uint16{80, 443})
+	c.Check(opts.Ratios, DeepEquals, []float32{1.5})
+	c.Check(opts.Offsets, DeepEquals, []int32{-1, 2})
+}
+
+func (s *MySuite) TestNumericOutOfRange(c *C) {
+	_, ap := createNumTestParser()
+
+	results := ap.parseArgv([]string{"--port=70000"})
+	c.Check(results.parseError, ErrorMatches,
+		"While parsing value for --port: 70000 is out of range for uint16")
+
+	_, ap = createNumTestParser()
+	results = ap.parseArgv([]string{"--int8", "128"})
+	c.Check(results.parseError, ErrorMatches,
+		"While parsing value for --int8: 128 is out of range for int8")
+
+	_, ap = createNumTestParser()
+	results = ap.parseArgv([]string{"--ratio", "1e40"})
+	c.Check(results.parseError, ErrorMatches,
+		"While parsing value for --ratio: 1e40 is out of range for float32")
+
+	_, ap = createNumTestParser()
+	results = ap.parseArgv([]string{"--ports", "80", "--ports", "65536"})
+	c.Check(results.parseError, ErrorMatches,
+		"While parsing value for --ports: 65536 is out of range for uint16")
+}
+
+func (s *MySuite) TestNumericInvalid(c *C) {
+	_, ap := createNumTestParser()
+
+	results := ap.parseArgv([]string{"--uint", "-1"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --uint: Cannot convert "-1" to uint`)
+}
+
+func (s *MySuite) TestNumericChoices(c *C) {
+	opts := &NumTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--port"},
+		Choices:  []uint16{80, 443},
+	})
+
+	results := ap.parseArgv([]string{"--port", "443"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Port, Equals, uint16(443))
+
+	results = ap.parseArgv([]string{"--port", "8080"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --port: Not a valid choice. Should be one of: \[80 443\]`)
+}
+
+func (s *MySuite) TestNumericIntRange(c *C) {
+	opts, ap := createPTestParser()
+
+	results := ap.parseArgv([]string{"--int1", "99999999999999999999"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --int1: Cannot convert "99999999999999999999" to an integer: .*value out of range`)
+	c.Check(opts.Int1, Equals, 0)
+}
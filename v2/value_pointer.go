@@ -0,0 +1,101 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements values for pointer destinations, like *int, which
+// stay nil unless the argument is seen.
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A pointer to a scalar type. The item is parsed into a scratch value
+// by the value type for the pointed-to type, and each time a value is
+// stored, a new item is allocated for the destination to point to. That
+// way a default the caller put in the field is never overwritten.
+type pointerValueT struct {
+	valueT
+	elemType reflect.Type
+	elem     valueType
+	scratch  reflect.Value
+}
+
+func newPointerValueT(valueP reflect.Value, arg *Argument) (*pointerValueT, error) {
+	fieldType := valueP.Type()
+	elemType := fieldType.Elem()
+
+	switch elemType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return nil, fmt.Errorf("cannot be of type %s", fieldType.String())
+	}
+
+	scratch := reflect.New(elemType).Elem()
+	elem, err := newValueType(scratch, arg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot be of type %s", fieldType.String())
+	}
+	return &pointerValueT{
+		valueT:   valueT{valueP},
+		elemType: elemType,
+		elem:     elem,
+		scratch:  scratch,
+	}, nil
+}
+
+// Point the destination at a new copy of the scratch value
+func (self *pointerValueT) store() {
+	item := reflect.New(self.elemType)
+	item.Elem().Set(self.scratch)
+	self.value.Set(item)
+}
+
+func (self *pointerValueT) defaultSwitchNumArgs() int {
+	return self.elem.defaultSwitchNumArgs()
+}
+
+func (self *pointerValueT) seenWithoutValue(m *Messages) error {
+	err := self.elem.seenWithoutValue(m)
+	if err != nil {
+		return err
+	}
+	self.store()
+	return nil
+}
+
+func (self *pointerValueT) parse(m *Messages, text string) error {
+	err := self.elem.parse(m, text)
+	if err != nil {
+		return err
+	}
+	self.store()
+	return nil
+}
+
+// Copy the pointed-to item, so that a sub-command inheriting the value
+// does not share storage with its parent.
+func (self *pointerValueT) setValue(valueP reflect.Value) {
+	if valueP.IsNil() {
+		self.value.Set(reflect.Zero(self.value.Type()))
+		return
+	}
+	self.scratch.Set(valueP.Elem())
+	self.store()
+}
+
+func (self *pointerValueT) setChoices(m *Messages, choicesIntf interface{}) error {
+	return self.elem.setChoices(m, choicesIntf)
+}
+
+func (self *pointerValueT) storageType() valueStorageType {
+	return Scalar
+}
+
+// Is the value type for a bool, or a pointer to a bool?
+func isBoolValueType(value valueType) bool {
+	if pointer, ok := value.(*pointerValueT); ok {
+		value = pointer.elem
+	}
+	_, ok := value.(*boolValueT)
+	return ok
+}
@@ -0,0 +1,172 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type PtrTestOptions struct {
+	Count   *int
+	Name    *string
+	Verbose *bool
+	Timeout *time.Duration
+	Port    *uint16
+	Mode    *string
+}
+
+func createPtrTestParser() (*PtrTestOptions, *ArgumentParser) {
+	opts := &PtrTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--count"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--name"},
+	})
+	ap.Add(&Argument{
+		Switches:  []string{"--verbose"},
+		Negatable: true,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--timeout"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--port"},
+		Choices:  []uint16{80, 443},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--fast"},
+		Dest:     "Mode",
+		Action:   ActionStoreConst,
+		Const:    "fast",
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestPointerNotSeen(c *C) {
+	opts, ap := createPtrTestParser()
+
+	results := ap.parseArgv([]string{})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Count, IsNil)
+	c.Check(opts.Name, IsNil)
+	c.Check(opts.Verbose, IsNil)
+	c.Check(opts.Timeout, IsNil)
+	c.Check(opts.Port, IsNil)
+	c.Check(opts.Mode, IsNil)
+}
+
+func (s *MySuite) TestPointerZeroValues(c *C) {
+	opts, ap := createPtrTestParser()
+
+	results := ap.parseArgv([]string{"--count", "0", "--name", "", "--no-verbose",
+		"--timeout", "0s", "--port", "80", "--fast"})
+	c.Assert(results.parseError, IsNil)
+	c.Assert(opts.Count, NotNil)
+	c.Check(*opts.Count, Equals, 0)
+	c.Assert(opts.Name, NotNil)
+	c.Check(*opts.Name, Equals, "")
+	c.Assert(opts.Verbose, NotNil)
+	c.Check(*opts.Verbose, Equals, false)
+	c.Assert(opts.Timeout, NotNil)
+	c.Check(*opts.Timeout, Equals, time.Duration(0))
+	c.Assert(opts.Port, NotNil)
+	c.Check(*opts.Port, Equals, uint16(80))
+	c.Assert(opts.Mode, NotNil)
+	c.Check(*opts.Mode, Equals, "fast")
+}
+
+func (s *MySuite) TestPointerBoolSwitch(c *C) {
+	opts, ap := createPtrTestParser()
+
+	results := ap.parseArgv([]string{"--verbose"})
+	c.Assert(results.parseError, IsNil)
+	c.Assert(opts.Verbose, NotNil)
+	c.Check(*opts.Verbose, Equals, true)
+}
+
+func (s *MySuite) TestPointerDefaultNotOverwritten(c *C) {
+	opts, ap := createPtrTestParser()
+	defaultCount := 5
+	opts.Count = &defaultCount
+
+	results := ap.parseArgv([]string{"--count", "6"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(*opts.Count, Equals, 6)
+	c.Check(defaultCount, Equals, 5)
+}
+
+func (s *MySuite) TestPointerBadValue(c *C) {
+	opts, ap := createPtrTestParser()
+
+	results := ap.parseArgv([]string{"--port", "8080"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --port: Not a valid choice. Should be one of: \[80 443\]`)
+	c.Check(opts.Port, IsNil)
+}
+
+func (s *MySuite) TestPointerBadType(c *C) {
+	type BadOptions struct {
+		Counts *[]int
+	}
+	ap := New(&Command{
+		Values: &BadOptions{},
+	})
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--counts"},
+		})
+	}, PanicMatches, `Argument --counts cannot be of type \*\[\]int`)
+}
+
+func (s *MySuite) TestPointerInherited(c *C) {
+	opts := &PtrTestOptions{}
+	subOpts := &PtrTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--count"},
+		Inherit:  true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"--count", "0", "sub"})
+	c.Assert(results.parseError, IsNil)
+	c.Assert(opts.Count, NotNil)
+	c.Assert(subOpts.Count, NotNil)
+	c.Check(*subOpts.Count, Equals, 0)
+
+	// The sub-command has its own copy
+	c.Check(subOpts.Count == opts.Count, Equals, false)
+}
+
+func (s *MySuite) TestPointerInheritedNotSeen(c *C) {
+	opts := &PtrTestOptions{}
+	subOpts := &PtrTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--count"},
+		Inherit:  true,
+	})
+	ap.New(&Command{
+		Name:   "sub",
+		Values: subOpts,
+	})
+
+	results := ap.parseArgv([]string{"sub"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Count, IsNil)
+	c.Check(subOpts.Count, IsNil)
+}
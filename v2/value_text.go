@@ -0,0 +1,186 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+// This file implements values for types that can parse themselves,
+// via encoding.TextUnmarshaler or flag.Value, using a generic
+// value type that other item types can use too.
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// Parse text into a new item of some type
+type parseItemFunc func(m *Messages, text string) (reflect.Value, error)
+
+// If the destination can parse itself, return the function that parses
+// text into it, and the default number of args for a switch (0 for
+// a flag.Value that says it is a bool flag).
+func selfParsingItemParser(valueP reflect.Value) (parseItemFunc, int, bool) {
+	itemType := valueP.Type()
+
+	// A flag.Value is Set on a copy of the destination, so that it can
+	// accumulate values, as it does with the flag module. The copy is
+	// stored only if the value is accepted.
+	if valueP.CanAddr() && reflect.PtrTo(itemType).Implements(flagValueType) {
+		flagValue := valueP.Addr().Interface().(flag.Value)
+		numArgs := 1
+		if boolFlag, ok := flagValue.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			numArgs = 0
+		}
+		parseItem := func(m *Messages, text string) (reflect.Value, error) {
+			item := reflect.New(itemType)
+			item.Elem().Set(valueP)
+			err := item.Interface().(flag.Value).Set(text)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as %s: %w",
+					text, itemType.String(), err)
+			}
+			return item.Elem(), nil
+		}
+		return parseItem, numArgs, true
+	}
+
+	parseItem, ok := selfParsingSliceItemParser(itemType)
+	return parseItem, 1, ok
+}
+
+// If the item type can parse itself, return the function that parses
+// text into a new item
+func selfParsingSliceItemParser(itemType reflect.Type) (parseItemFunc, bool) {
+	switch {
+	case itemType.Kind() == reflect.Ptr && itemType.Implements(textUnmarshalerType):
+		return func(m *Messages, text string) (reflect.Value, error) {
+			item := reflect.New(itemType.Elem())
+			err := item.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as %s: %w",
+					text, itemType.String(), err)
+			}
+			return item, nil
+		}, true
+
+	case reflect.PtrTo(itemType).Implements(textUnmarshalerType):
+		return func(m *Messages, text string) (reflect.Value, error) {
+			item := reflect.New(itemType)
+			err := item.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as %s: %w",
+					text, itemType.String(), err)
+			}
+			return item.Elem(), nil
+		}, true
+
+	case itemType.Kind() == reflect.Ptr && itemType.Implements(flagValueType):
+		return func(m *Messages, text string) (reflect.Value, error) {
+			item := reflect.New(itemType.Elem())
+			err := item.Interface().(flag.Value).Set(text)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as %s: %w",
+					text, itemType.String(), err)
+			}
+			return item, nil
+		}, true
+
+	case reflect.PtrTo(itemType).Implements(flagValueType):
+		return func(m *Messages, text string) (reflect.Value, error) {
+			item := reflect.New(itemType)
+			err := item.Interface().(flag.Value).Set(text)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("Cannot parse \"%s\" as %s: %w",
+					text, itemType.String(), err)
+			}
+			return item.Elem(), nil
+		}, true
+	}
+	return nil, false
+}
+
+// =========================================================== func
+
+// A value type for items of any type, parsed by a parseItemFunc, and
+// either stored or appended to a slice. Choices are compared by how
+// they print, so fmt.Stringer types can be compared with each other,
+// or with a []string of Choices.
+type funcValueT struct {
+	valueT
+	itemType  reflect.Type
+	parseItem parseItemFunc
+	storage   valueStorageType
+	numArgs   int
+	choices   []string
+}
+
+func newFuncValueT(valueP reflect.Value, itemType reflect.Type, parseItem parseItemFunc,
+	storage valueStorageType, numArgs int) *funcValueT {
+	return &funcValueT{
+		valueT:    valueT{valueP},
+		itemType:  itemType,
+		parseItem: parseItem,
+		storage:   storage,
+		numArgs:   numArgs,
+	}
+}
+
+func (self *funcValueT) defaultSwitchNumArgs() int {
+	return self.numArgs
+}
+
+func (self *funcValueT) seenWithoutValue(m *Messages) error {
+	if self.numArgs == 0 {
+		return self.parse(m, "true")
+	}
+	return fmt.Errorf("Need a %s value", self.itemType.String())
+}
+
+func (self *funcValueT) parse(m *Messages, text string) error {
+	item, err := self.parseItem(m, text)
+	if err != nil {
+		return err
+	}
+	if len(self.choices) > 0 {
+		printed := fmt.Sprint(item.Interface())
+		ok := false
+		for _, choice := range self.choices {
+			if printed == choice {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf(m.ShouldBeAValidChoiceFmt, self.choices)
+		}
+	}
+	if self.storage == Slice {
+		self.value.Set(reflect.Append(self.value, item))
+	} else {
+		self.value.Set(item)
+	}
+	return nil
+}
+
+func (self *funcValueT) setChoices(m *Messages, choicesIntf interface{}) error {
+	choicesValue := reflect.ValueOf(choicesIntf)
+	if choicesValue.Kind() != reflect.Slice {
+		return fmt.Errorf(m.ChoicesOfWrongTypeFmt, self.itemType.String())
+	}
+	elemType := choicesValue.Type().Elem()
+	if elemType != self.itemType && elemType.Kind() != reflect.String {
+		return fmt.Errorf(m.ChoicesOfWrongTypeFmt, self.itemType.String())
+	}
+	self.choices = make([]string, choicesValue.Len())
+	for i := 0; i < choicesValue.Len(); i++ {
+		self.choices[i] = fmt.Sprint(choicesValue.Index(i).Interface())
+	}
+	return nil
+}
+
+func (self *funcValueT) storageType() valueStorageType {
+	return self.storage
+}
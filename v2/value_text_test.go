@@ -0,0 +1,222 @@
+package argparse
+
+// Copyright (c) 2026 by Gilbert Ramirez <gram@alumni.rice.edu>
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+// Implements encoding.TextUnmarshaler and fmt.Stringer
+type textLevel int
+
+func (self *textLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*self = 1
+	case "high":
+		*self = 2
+	default:
+		return fmt.Errorf("unknown level %s", text)
+	}
+	return nil
+}
+
+func (self textLevel) String() string {
+	switch self {
+	case 1:
+		return "low"
+	case 2:
+		return "high"
+	}
+	return "none"
+}
+
+// Implements flag.Value, accumulating values like some flag.Values do
+type flagList struct {
+	items []string
+}
+
+func (self *flagList) String() string {
+	return strings.Join(self.items, ",")
+}
+
+func (self *flagList) Set(text string) error {
+	self.items = append(self.items, text)
+	return nil
+}
+
+// Implements flag.Value, as a bool flag
+type flagSwitch struct {
+	on bool
+}
+
+func (self *flagSwitch) String() string {
+	return fmt.Sprint(self.on)
+}
+
+func (self *flagSwitch) Set(text string) error {
+	self.on = text == "true"
+	return nil
+}
+
+func (self *flagSwitch) IsBoolFlag() bool {
+	return true
+}
+
+// Implements flag.Value, storing the name in lower case
+type flagName string
+
+func (self *flagName) String() string {
+	return string(*self)
+}
+
+func (self *flagName) Set(text string) error {
+	*self = flagName(strings.ToLower(text))
+	return nil
+}
+
+type TTestOptions struct {
+	Level  textLevel
+	Levels []textLevel
+	List   flagList
+	Switch flagSwitch
+}
+
+func createTTestParser() (*TTestOptions, *ArgumentParser) {
+	opts := &TTestOptions{}
+	ap := New(&Command{
+		Description: "This is a test program",
+		Values:      opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--levels"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--list"},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--switch"},
+	})
+	return opts, ap
+}
+
+func (s *MySuite) TestTextUnmarshaler(c *C) {
+	opts, ap := createTTestParser()
+
+	argv := []string{"--level", "high"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Level, Equals, textLevel(2))
+}
+
+func (s *MySuite) TestTextUnmarshalerError(c *C) {
+	_, ap := createTTestParser()
+
+	argv := []string{"--level", "medium"}
+	results := ap.parseArgv(argv)
+
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --level: Cannot parse "medium" as argparse.textLevel: unknown level medium`)
+}
+
+func (s *MySuite) TestTextUnmarshalerSlice(c *C) {
+	opts, ap := createTTestParser()
+
+	argv := []string{"--levels", "high", "--levels=low"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Levels, DeepEquals, []textLevel{2, 1})
+}
+
+func (s *MySuite) TestTextUnmarshalerChoices(c *C) {
+	opts := &TTestOptions{}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+		Choices:  []textLevel{2},
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--levels"},
+		Choices:  []string{"low"},
+	})
+
+	results := ap.parseArgv([]string{"--level", "high", "--levels", "low"})
+	c.Assert(results.parseError, IsNil)
+
+	results = ap.parseArgv([]string{"--level", "low"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --level: Not a valid choice. Should be one of: \[high\]`)
+}
+
+func (s *MySuite) TestTextUnmarshalerChoicesWrongType(c *C) {
+	_, ap := createTTestParser()
+	c.Check(func() {
+		ap.Add(&Argument{
+			Switches: []string{"--other-level"},
+			Dest:     "Level",
+			Choices:  []int{1},
+		})
+	}, PanicMatches, `Argument --other-level: Choices should be \[\]argparse.textLevel`)
+}
+
+func (s *MySuite) TestFlagValue(c *C) {
+	opts, ap := createTTestParser()
+
+	argv := []string{"--list", "a", "--list", "b", "--switch"}
+	results := ap.parseArgv(argv)
+
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.List.items, DeepEquals, []string{"a", "b"})
+	c.Check(opts.Switch.on, Equals, true)
+}
+
+// A flag.Value that is not one of the Choices is not stored
+func (s *MySuite) TestFlagValueChoices(c *C) {
+	opts := &struct {
+		Level flagName
+	}{
+		Level: "info",
+	}
+	ap := New(&Command{
+		Values: opts,
+	})
+	ap.Add(&Argument{
+		Switches: []string{"--level"},
+		Choices:  []string{"info", "debug"},
+	})
+
+	results := ap.parseArgv([]string{"--level", "BOGUS"})
+	c.Check(results.parseError, ErrorMatches,
+		`While parsing value for --level: Not a valid choice. Should be one of: \[info debug\]`)
+	c.Check(opts.Level, Equals, flagName("info"))
+
+	results = ap.parseArgv([]string{"--level", "DEBUG"})
+	c.Assert(results.parseError, IsNil)
+	c.Check(opts.Level, Equals, flagName("debug"))
+}
+
+func (s *MySuite) TestFlagValueSwitchMessages(c *C) {
+	opts := &TTestOptions{}
+	valueP := reflect.ValueOf(opts).Elem().FieldByName("Switch")
+	value, err := newValueType(valueP, &Argument{})
+	c.Assert(err, IsNil)
+	err = value.setChoices(&DefaultMessages_en, []flagSwitch{{on: false}})
+	c.Assert(err, IsNil)
+
+	// The Messages of the parser are used for the error
+	m := DefaultMessages_en
+	m.ShouldBeAValidChoiceFmt = "Choix invalide: %v"
+	err = value.seenWithoutValue(&m)
+	c.Check(err, ErrorMatches, "Choix invalide: .*")
+}